@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentApiAndService uses the API while the service is used directly,
+// as the UI does, to be run with the race detector: go test -race ./...
+func TestConcurrentApiAndService(t *testing.T) {
+	logService := service.NewLogService(t.TempDir())
+	router := NewRouter(0, logService)
+	router.Init()
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			response := doRequest(router, http.MethodPost, "/api/v1/days/2026-10-18/entries", map[string]interface{}{"name": fmt.Sprintf("api %v", i), "mark": "task"})
+			assert.Equal(t, http.StatusCreated, response.Code)
+			var created entryResponse
+			assert.Nil(t, json.NewDecoder(response.Body).Decode(&created))
+			response = doRequest(router, http.MethodPatch, "/api/v1/entries/"+created.Entry.Id, map[string]interface{}{"mark": "complete"})
+			assert.Equal(t, http.StatusOK, response.Code)
+			response = doRequest(router, http.MethodGet, "/api/v1/days/2026-10-18", nil)
+			assert.Equal(t, http.StatusOK, response.Code)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			dailyLog, err := logService.AddNewLog(date, fmt.Sprintf("ui %v", i), model.Note)
+			assert.Nil(t, err)
+			_, err = logService.SetImportant(dailyLog.Logs[len(dailyLog.Logs)-1].Id, true)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	dailyLog, err := logService.ReadDay(date)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 40)
+}
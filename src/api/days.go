@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/apoloa/bjournal/src/model"
+)
+
+// handleDays serves /api/v1/days/{date} and /api/v1/days/{date}/entries.
+func (r *Router) handleDays(w http.ResponseWriter, req *http.Request) {
+	segments := pathSegments(daysPath, req.URL.Path)
+	if len(segments) == 0 || len(segments) > 2 || (len(segments) == 2 && segments[1] != "entries") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	date, err := parseDate(segments[0])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(segments) == 2 {
+		if req.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		var body entryRequest
+		if err := decodeBody(req, &body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := body.validate(); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		log := model.NewLog(body.Name, body.Mark)
+		log.Important = body.Important
+		log.Due, log.At = body.Due, body.At
+		var dailyLog model.DailyLog
+		if body.ParentId != "" {
+			dailyLog, err = r.logService.AppendLog(body.ParentId, date, log)
+		} else {
+			dailyLog, err = r.logService.AddLog(date, log)
+		}
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		// The stored log has the tags parsed by the service.
+		if stored := dailyLog.Find(log.Id); stored != nil {
+			log = *stored
+		}
+		writeJSON(w, http.StatusCreated, entryResponse{Date: date.Format(dateLayout), Entry: log})
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		dailyLog, err := r.logService.ReadDay(date)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, dayResponse{Date: date.Format(dateLayout), Logs: dailyLog.Logs})
+	case http.MethodPut:
+		var body dayRequest
+		if err := decodeBody(req, &body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := validateLogs(body.Logs); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if body.Logs == nil {
+			body.Logs = []model.Log{}
+		}
+		dailyLog, err := r.logService.SaveDay(date, body.Logs)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, dayResponse{Date: date.Format(dateLayout), Logs: dailyLog.Logs})
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
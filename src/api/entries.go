@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+)
+
+// handleEntries serves /api/v1/entries/{id}, /api/v1/entries/{id}/entries and
+// /api/v1/entries/{id}/important.
+func (r *Router) handleEntries(w http.ResponseWriter, req *http.Request) {
+	segments := pathSegments(entriesPath, req.URL.Path)
+	if len(segments) == 0 || len(segments) > 2 || (len(segments) == 2 && segments[1] != "entries" && segments[1] != "important") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	id := segments[0]
+
+	if len(segments) == 2 {
+		if req.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		if segments[1] == "important" {
+			r.toggleImportant(w, id)
+			return
+		}
+		r.createSubEntry(w, req, id)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		date, log, err := r.logService.FindLog(id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, entryResponse{Date: date.Format(dateLayout), Entry: log})
+	case http.MethodPatch:
+		r.patchEntry(w, req, id)
+	case http.MethodDelete:
+		if err := r.logService.DeleteLog(id); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+	}
+}
+
+func (r *Router) createSubEntry(w http.ResponseWriter, req *http.Request, parentId string) {
+	var body entryRequest
+	if err := decodeBody(req, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.ParentId != "" && body.ParentId != parentId {
+		writeError(w, http.StatusBadRequest, errors.New("the parent_id doesn't match the URL"))
+		return
+	}
+	if err := body.validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	date, _, err := r.logService.FindLog(parentId)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	log := model.NewLog(body.Name, body.Mark)
+	log.Important = body.Important
+	log.Due, log.At = body.Due, body.At
+	dailyLog, err := r.logService.AppendLog(parentId, date, log)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	// The stored log has the tags parsed by the service.
+	if stored := dailyLog.Find(log.Id); stored != nil {
+		log = *stored
+	}
+	writeJSON(w, http.StatusCreated, entryResponse{Date: date.Format(dateLayout), Entry: log})
+}
+
+// toggleImportant marks the entry as important, or removes the mark.
+func (r *Router) toggleImportant(w http.ResponseWriter, id string) {
+	date, log, err := r.logService.ToggleImportant(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entryResponse{Date: date.Format(dateLayout), Entry: log})
+}
+
+func (r *Router) patchEntry(w http.ResponseWriter, req *http.Request, id string) {
+	var body patchRequest
+	if err := decodeBody(req, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.Name != nil && strings.TrimSpace(*body.Name) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("the name is empty"))
+		return
+	}
+	date, log, err := r.logService.UpdateLog(id, func(log *model.Log) error {
+		if body.Mark != nil && *body.Mark != log.Mark && !log.MarkAs(*body.Mark) {
+			return fmt.Errorf("%w: %v can't be marked as %v", service.ErrInvalidMark, log.Mark, *body.Mark)
+		}
+		if body.Name != nil {
+			log.Name = *body.Name
+		}
+		if body.Important != nil {
+			log.Important = *body.Important
+		}
+		if body.Due != nil {
+			log.Due = body.Due
+		}
+		if body.At != nil {
+			log.At = body.At
+		}
+		return nil
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entryResponse{Date: date.Format(dateLayout), Entry: log})
+}
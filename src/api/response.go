@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+	zerolog "github.com/rs/zerolog/log"
+)
+
+// errorResponse is the body of every failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type dayResponse struct {
+	Date string      `json:"date"`
+	Logs []model.Log `json:"logs"`
+}
+
+type entryResponse struct {
+	Date  string    `json:"date"`
+	Entry model.Log `json:"entry"`
+}
+
+// entryRequest is the body to create an entry.
+type entryRequest struct {
+	Name      string         `json:"name"`
+	Mark      model.Category `json:"mark"`
+	Important bool           `json:"important"`
+	ParentId  string         `json:"parent_id,omitempty"`
+	Due       *model.Moment  `json:"due,omitempty"`
+	At        *model.Moment  `json:"at,omitempty"`
+}
+
+// patchRequest is the body to update an entry, only the given fields change.
+type patchRequest struct {
+	Name      *string         `json:"name"`
+	Mark      *model.Category `json:"mark"`
+	Important *bool           `json:"important"`
+	Due       *model.Moment   `json:"due"`
+	At        *model.Moment   `json:"at"`
+}
+
+// dayRequest is the body to replace the entries of a day.
+type dayRequest struct {
+	Logs []model.Log `json:"logs"`
+}
+
+func (e entryRequest) validate() error {
+	if strings.TrimSpace(e.Name) == "" {
+		return errors.New("the name is empty")
+	}
+	switch e.Mark {
+	case model.Task, model.Note, model.Event:
+		return nil
+	}
+	return fmt.Errorf("invalid mark %q, expected task, note or event", e.Mark)
+}
+
+func validateLogs(logs []model.Log) error {
+	for _, log := range logs {
+		if strings.TrimSpace(log.Name) == "" {
+			return errors.New("the name is empty")
+		}
+		if !log.Mark.IsValid() {
+			return fmt.Errorf("invalid mark %q", log.Mark)
+		}
+		if log.SubLogs != nil {
+			if err := validateLogs(*log.SubLogs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		zerolog.Print("Error writing the response ", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeServiceError writes the error of the service with its status code.
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrLogNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrInvalidMark):
+		writeError(w, http.StatusConflict, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
@@ -1,12 +1,22 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/apoloa/bjournal/src/service"
+	zerolog "github.com/rs/zerolog/log"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	daysPath    = "/api/v1/days/"
+	entriesPath = "/api/v1/entries/"
+	searchPath  = "/api/v1/search"
+	tagsPath    = "/api/v1/tags"
 )
 
 type Router struct {
@@ -23,17 +33,23 @@ func (r *Router) Init() {
 	r.router.HandleFunc("/api/log/today", func(writer http.ResponseWriter, request *http.Request) {
 		day, err := r.logService.ReadDay(time.Now())
 		if err != nil {
+			writeError(writer, http.StatusInternalServerError, err)
 			return
 		}
-		err = json.NewEncoder(writer).Encode(day)
-		if err != nil {
-			return
-		}
-
+		writeJSON(writer, http.StatusOK, day)
 	})
 	r.router.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
-		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 	})
+	r.router.HandleFunc(daysPath, r.handleDays)
+	r.router.HandleFunc(entriesPath, r.handleEntries)
+	r.router.HandleFunc(searchPath, r.handleSearch)
+	r.router.HandleFunc(tagsPath, r.handleTags)
+}
+
+// Handler returns the handler with every route, Init must be called first.
+func (r *Router) Handler() http.Handler {
+	return r.router
 }
 
 func (r *Router) Start() {
@@ -46,6 +62,27 @@ func (r *Router) Start() {
 	}
 	err := srv.ListenAndServe()
 	if err != nil {
-		return
+		zerolog.Print("Error starting the API ", err)
+	}
+}
+
+// pathSegments returns the segments of the path after the prefix.
+func pathSegments(prefix, path string) []string {
+	path = strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// parseDate parses a date of the URL, "today" is accepted as well.
+func parseDate(value string) (time.Time, error) {
+	if value == "today" {
+		return time.Now(), nil
+	}
+	date, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return date, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
 	}
+	return date, nil
 }
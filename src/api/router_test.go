@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouter(t *testing.T) *Router {
+	router := NewRouter(0, service.NewLogService(t.TempDir()))
+	router.Init()
+	return router
+}
+
+func doRequest(router *Router, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buffer bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buffer).Encode(body)
+	}
+	recorder := httptest.NewRecorder()
+	router.Handler().ServeHTTP(recorder, httptest.NewRequest(method, path, &buffer))
+	return recorder
+}
+
+func TestEntriesLifecycle(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(router, http.MethodPost, "/api/v1/days/2026-10-18/entries", map[string]interface{}{"name": "Call bank #money @phone", "mark": "task"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+	var created entryResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&created))
+	assert.Equal(t, "2026-10-18", created.Date)
+	assert.NotEmpty(t, created.Entry.Id)
+	assert.Equal(t, []string{"money"}, created.Entry.Tags)
+	assert.Equal(t, []string{"phone"}, created.Entry.Contexts)
+
+	response = doRequest(router, http.MethodPost, "/api/v1/entries/"+created.Entry.Id+"/entries", map[string]interface{}{"name": "Ask for the receipt #money", "mark": "note"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+	var child entryResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&child))
+	assert.Equal(t, []string{"money"}, child.Entry.Tags)
+
+	response = doRequest(router, http.MethodPatch, "/api/v1/entries/"+created.Entry.Id, map[string]interface{}{"mark": "complete", "important": true, "name": "Call the bank"})
+	assert.Equal(t, http.StatusOK, response.Code)
+	var patched entryResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&patched))
+	assert.Equal(t, model.Complete, patched.Entry.Mark)
+	assert.True(t, patched.Entry.Important)
+	assert.Equal(t, "Call the bank", patched.Entry.Name)
+
+	response = doRequest(router, http.MethodPost, "/api/v1/entries/"+created.Entry.Id+"/important", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&patched))
+	assert.False(t, patched.Entry.Important)
+
+	response = doRequest(router, http.MethodPatch, "/api/v1/entries/"+created.Entry.Id, map[string]interface{}{"mark": "irrelevant"})
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	response = doRequest(router, http.MethodGet, "/api/v1/days/2026-10-18", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var day dayResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&day))
+	assert.Len(t, day.Logs, 1)
+	assert.Len(t, *day.Logs[0].SubLogs, 1)
+
+	response = doRequest(router, http.MethodDelete, "/api/v1/entries/"+created.Entry.Id, nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(router, http.MethodGet, "/api/v1/entries/"+created.Entry.Id, nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	var failure errorResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&failure))
+	assert.NotEmpty(t, failure.Error)
+}
+
+func TestReplaceDay(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(router, http.MethodPut, "/api/v1/days/2026-10-18", map[string]interface{}{
+		"logs": []map[string]interface{}{{"name": "Standup", "mark": "event"}},
+	})
+	assert.Equal(t, http.StatusOK, response.Code)
+	var day dayResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&day))
+	assert.Len(t, day.Logs, 1)
+	assert.NotEmpty(t, day.Logs[0].Id)
+
+	response = doRequest(router, http.MethodPut, "/api/v1/days/2026-10-18", map[string]interface{}{
+		"logs": []map[string]interface{}{{"name": "Standup", "mark": "unknown"}},
+	})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = doRequest(router, http.MethodGet, "/api/v1/days/18-10-2026", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = doRequest(router, http.MethodDelete, "/api/v1/days/2026-10-18", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+}
+
+func TestSearch(t *testing.T) {
+	router := newTestRouter(t)
+	doRequest(router, http.MethodPost, "/api/v1/days/2026-10-18/entries", map[string]interface{}{"name": "Send the invoice", "mark": "task"})
+	doRequest(router, http.MethodPost, "/api/v1/days/2026-10-18/entries", map[string]interface{}{"name": "Invoice sent", "mark": "note"})
+
+	response := doRequest(router, http.MethodGet, "/api/v1/search?q="+url.QueryEscape(`mark:task text:"invoice"`), nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var results []entryResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&results))
+	assert.Len(t, results, 1)
+	assert.Equal(t, "2026-10-18", results[0].Date)
+	assert.Equal(t, "Send the invoice", results[0].Entry.Name)
+
+	response = doRequest(router, http.MethodGet, "/api/v1/search?q=mark:unknown", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestTags(t *testing.T) {
+	router := newTestRouter(t)
+	doRequest(router, http.MethodPost, "/api/v1/days/2026-10-18/entries", map[string]interface{}{"name": "Send the invoice #work @office", "mark": "task"})
+
+	response := doRequest(router, http.MethodGet, "/api/v1/tags", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var tags []model.TagCount
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&tags))
+	assert.Equal(t, []model.TagCount{{Tag: "#work", Count: 1}, {Tag: "@office", Count: 1}}, tags)
+}
+
+func TestDaysHaveTheirRecurringEntries(t *testing.T) {
+	dir := t.TempDir()
+	yesterday := time.Now().AddDate(0, 0, -1)
+	_, err := service.NewLogService(dir).AddRecurrence(model.NewRecurrence("daily", "Water plants", model.Task, yesterday.AddDate(0, 0, -7)))
+	assert.Nil(t, err)
+	router := NewRouter(0, service.NewLogService(dir))
+	router.Init()
+
+	response := doRequest(router, http.MethodGet, "/api/v1/days/"+yesterday.Format(dateLayout), nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var day dayResponse
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&day))
+	assert.Len(t, day.Logs, 1)
+	assert.Equal(t, "Water plants", day.Logs[0].Name)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/apoloa/bjournal/src/search"
+)
+
+// handleSearch serves GET /api/v1/search?q=<query>.
+func (r *Router) handleSearch(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	query, err := search.Parse(req.URL.Query().Get("q"), time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	results, err := r.logService.Search(query)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := make([]entryResponse, len(results))
+	for i, result := range results {
+		response[i] = entryResponse{Date: result.Date.Format(dateLayout), Entry: result.Log}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleTags serves GET /api/v1/tags.
+func (r *Router) handleTags(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	tags, err := r.logService.Tags()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
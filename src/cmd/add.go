@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addDate      string
+	addImportant bool
+	addUnder     string
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add an entry to the journal without opening the UI",
+	Long: `Add an entry to the journal without opening the UI.
+
+The text is taken from the arguments. Without arguments every non empty line
+of the standard input is added as a new entry. A moment written after ^ at the
+end of the text, as in "Pay rent ^2026-10-20 14:00", is when a task is due or
+an event happens.`,
+}
+
+func newAddCategoryCmd(name string, category model.Category) *cobra.Command {
+	return &cobra.Command{
+		Use:   fmt.Sprintf("%v [text]", name),
+		Short: fmt.Sprintf("Add a new %v", name),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAdd(cmd, args, category)
+		},
+	}
+}
+
+func runAdd(cmd *cobra.Command, args []string, category model.Category) error {
+	date, err := parseDate(addDate)
+	if err != nil {
+		return err
+	}
+	texts, err := addTexts(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	m, err := newLogService(cfg)
+	if err != nil {
+		return err
+	}
+	// Reading the day first adds its recurring entries before the new ones.
+	if _, err := m.ReadDay(date); err != nil {
+		return err
+	}
+	parentId, err := resolveParent(m, date, addUnder)
+	if err != nil {
+		return err
+	}
+	for _, text := range texts {
+		name, moment, err := model.ParseMomentText(text, date)
+		if err != nil {
+			return err
+		}
+		log := model.NewLog(name, category)
+		log.SetMoment(moment)
+		log.Important = addImportant
+		var added *model.Log
+		if parentId == "" {
+			dailyLog, err := m.AddLog(date, log)
+			if err != nil {
+				return err
+			}
+			added = &dailyLog.Logs[len(dailyLog.Logs)-1]
+		} else {
+			dailyLog, err := m.AppendLog(parentId, date, log)
+			if err != nil {
+				return err
+			}
+			parent := dailyLog.Find(parentId)
+			added = &(*parent.SubLogs)[len(*parent.SubLogs)-1]
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", added.Id, formatLog(*added))
+	}
+	return nil
+}
+
+// addTexts returns the entry texts from the arguments or the standard input.
+func addTexts(args []string) ([]string, error) {
+	if len(args) > 0 {
+		text := strings.TrimSpace(strings.Join(args, " "))
+		if text == "" {
+			return nil, fmt.Errorf("the entry text is empty")
+		}
+		return []string{text}, nil
+	}
+	var texts []string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if text := strings.TrimSpace(scanner.Text()); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("the entry text is empty")
+	}
+	return texts, nil
+}
+
+// resolveParent returns the id of the parent entry, that can be given by its id
+// or by its position (starting at 1) in the day.
+func resolveParent(m *service.LogService, date time.Time, under string) (string, error) {
+	if under == "" {
+		return "", nil
+	}
+	position, err := strconv.Atoi(under)
+	if err != nil {
+		return under, nil
+	}
+	dailyLog, err := m.ReadDay(date)
+	if err != nil {
+		return "", err
+	}
+	if position < 1 || position > len(dailyLog.Logs) {
+		return "", fmt.Errorf("there is no entry %v in the day", position)
+	}
+	return dailyLog.Logs[position-1].Id, nil
+}
+
+func init() {
+	flags := addCmd.PersistentFlags()
+	flags.StringVar(&addDate, "date", "", "day of the entry as YYYY-MM-DD (default today)")
+	flags.BoolVar(&addImportant, "important", false, "mark the entry as important")
+	flags.StringVar(&addUnder, "under", "", "id or position of the parent entry")
+	addCmd.AddCommand(
+		newAddCategoryCmd("task", model.Task),
+		newAddCategoryCmd("note", model.Note),
+		newAddCategoryCmd("event", model.Event),
+	)
+	rootCmd.AddCommand(addCmd)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agendaFrom string
+	agendaTo   string
+	agendaDays int
+)
+
+var agendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "Print the tasks due and the events coming up, and the overdue tasks",
+	Long: `Print the tasks still open after they were due, and then the tasks due
+and the events happening from --from to --to, by day and time. By default the
+agenda covers today and the following days up to --days.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to, err := agendaRange()
+		if err != nil {
+			return err
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		overdue, err := m.Overdue(time.Now())
+		if err != nil {
+			return err
+		}
+		agenda, err := m.Agenda(from, to)
+		if err != nil {
+			return err
+		}
+		printAgenda(cmd.OutOrStdout(), overdue, agenda)
+		return nil
+	},
+}
+
+// agendaRange returns the first and last day requested by the flags.
+func agendaRange() (time.Time, time.Time, error) {
+	from, err := parseDate(agendaFrom)
+	if err != nil {
+		return from, from, err
+	}
+	if agendaTo == "" {
+		if agendaDays < 1 {
+			return from, from, fmt.Errorf("--days must be at least 1")
+		}
+		return from, from.AddDate(0, 0, agendaDays-1), nil
+	}
+	to, err := parseDate(agendaTo)
+	if err != nil {
+		return from, to, err
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("--to is before --from")
+	}
+	return from, to, nil
+}
+
+// printAgenda prints the overdue tasks and then the agenda with a title for
+// every day of a moment.
+func printAgenda(w io.Writer, overdue, agenda []search.Result) {
+	if len(overdue) > 0 {
+		fmt.Fprintln(w, "Overdue")
+		for _, result := range overdue {
+			fmt.Fprintf(w, "  %v\t%v\n", result.Log.Id, formatLog(result.Log))
+		}
+	}
+	day := ""
+	for _, result := range agenda {
+		moment := result.Log.Moment()
+		if date := moment.Time.Format(dateLayout); date != day {
+			day = date
+			fmt.Fprintln(w, dayTitle(day))
+		}
+		fmt.Fprintf(w, "  %v\t%v\n", result.Log.Id, formatLog(result.Log))
+	}
+}
+
+func init() {
+	flags := agendaCmd.Flags()
+	flags.StringVar(&agendaFrom, "from", "", "first day of the agenda (default today)")
+	flags.StringVar(&agendaTo, "to", "", "last day of the agenda")
+	flags.IntVar(&agendaDays, "days", 7, "number of days of the agenda when --to is not given")
+	rootCmd.AddCommand(agendaCmd)
+}
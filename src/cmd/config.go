@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/apoloa/bjournal/src/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		bytes, err := cfg.ToBytes()
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), string(bytes))
+		return nil
+	},
+}
+
+var configInitForce bool
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write the default configuration file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p := configFilePath()
+		if err := config.Init(p, configInitForce); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), p)
+		return nil
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the configuration file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Fprintln(cmd.OutOrStdout(), configFilePath())
+	},
+}
+
+func configFilePath() string {
+	if configPath != "" {
+		return configPath
+	}
+	return config.Path()
+}
+
+func init() {
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "overwrite an existing config file")
+	configCmd.AddCommand(configShowCmd, configInitCmd, configPathCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/apoloa/bjournal/src/scheduler"
+	"github.com/spf13/cobra"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the hooks of the events and overdue tasks without opening the UI",
+	Long: `Run in the foreground the hooks of the config, that the UI runs while it
+is open, until it is interrupted. Don't run it while the UI is open or the hooks
+fire twice.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if len(cfg.Hooks) == 0 {
+			return fmt.Errorf("there are no hooks in the config")
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		fmt.Fprintf(cmd.OutOrStdout(), "Running %d hooks\n", len(cfg.Hooks))
+		scheduler.NewScheduler(m, cfg.Hooks).Run(ctx, scheduler.DefaultInterval)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+}
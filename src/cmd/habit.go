@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	habitTarget string
+	habitDate   string
+	habitUndo   bool
+)
+
+var habitCmd = &cobra.Command{
+	Use:   "habit",
+	Short: "Manage the habit tracker",
+	Long: `Manage the habits, things to do a number of times every day, week or
+month. They are stored in the habits.yaml file of the journal and the days they
+are done in the daily logs.`,
+}
+
+var habitAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a habit",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habit, err := model.NewHabit(strings.Join(args, " "), habitTarget)
+		if err != nil {
+			return err
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		if _, err := m.AddHabit(habit); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", habit.Name, habit.Target())
+		return nil
+	},
+}
+
+var habitCheckCmd = &cobra.Command{
+	Use:   "check <name>",
+	Short: "Mark a habit as done today, or on --date",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDate(habitDate)
+		if err != nil {
+			return err
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		habit, err := m.CheckHabit(date, strings.Join(args, " "), !habitUndo)
+		if err != nil {
+			return err
+		}
+		checkIns, err := m.HabitCheckIns()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", date.Format(dateLayout), formatHabit(habit, checkIns[habit.Name], time.Now()))
+		return nil
+	},
+}
+
+var habitListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the habits with their streaks and completion rates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		habitLog, err := m.ReadHabits()
+		if err != nil {
+			return err
+		}
+		checkIns, err := m.HabitCheckIns()
+		if err != nil {
+			return err
+		}
+		for _, habit := range habitLog.Entries {
+			fmt.Fprintln(cmd.OutOrStdout(), formatHabit(habit, checkIns[habit.Name], time.Now()))
+		}
+		return nil
+	},
+}
+
+var habitDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a habit, the days it was done are kept",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		_, err = m.DeleteHabit(strings.Join(args, " "))
+		return err
+	},
+}
+
+// formatHabit returns the habit with its target, its streak and its
+// completion rate in the last days.
+func formatHabit(habit model.Habit, checkIns model.CheckIns, now time.Time) string {
+	streak := habit.Streak(checkIns, now)
+	rate := habit.CompletionRate(checkIns, now.AddDate(0, 0, 1-model.HabitRateDays), now)
+	return fmt.Sprintf("%v\t%v\tstreak %d\t%.0f%% in %d days", habit.Name, habit.Target(), streak, rate*100, model.HabitRateDays)
+}
+
+func init() {
+	habitAddCmd.Flags().StringVar(&habitTarget, "target", "daily", "how often, daily, weekly, monthly or times/period like 3/week")
+	flags := habitCheckCmd.Flags()
+	flags.StringVar(&habitDate, "date", "", "day the habit was done (default today)")
+	flags.BoolVar(&habitUndo, "undo", false, "mark the habit as not done")
+	habitCmd.AddCommand(habitAddCmd, habitCheckCmd, habitListCmd, habitDeleteCmd)
+	rootCmd.AddCommand(habitCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Print the days an entry was migrated through",
+	Long: `Print the copies of an entry along its migrations, from the day it was
+written to the last day it was migrated to, and how many times it was pushed
+forward. Entries migrated more times than migrationWarning get a warning.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		history, err := m.MigrationHistory(args[0])
+		if err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		for _, step := range history {
+			fmt.Fprintf(w, "%v\t%v %v\n", step.Log.Id, dayTitle(step.Date.Format(dateLayout)), formatLog(step.Log))
+		}
+		migrations := history[len(history)-1].Log.Migrations
+		fmt.Fprintf(w, "Migrated %d times\n", migrations)
+		if cfg.MigrationWarning > 0 && migrations > cfg.MigrationWarning {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: migrated more than %d times, do it, schedule it or drop it\n", cfg.MigrationWarning)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var importantCmd = &cobra.Command{
+	Use:   "important [id]",
+	Short: "Toggle the priority of an entry or print the important open tasks",
+	Long: `Mark the entry as important, or remove the mark when it was important
+already. Without an id, print the important tasks still open in the journal,
+the most recent days first.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		if len(args) == 1 {
+			_, log, err := m.ToggleImportant(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%v\t%v\n", log.Id, formatLog(log))
+			return nil
+		}
+		results, err := m.ImportantTasks()
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			fmt.Fprintf(w, "%v\t%v %v\n", result.Log.Id, result.Date.Format(dateLayout), formatLog(result.Log))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importantCmd)
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const monthLayout = "2006-01"
+
+var (
+	listDate   string
+	listFrom   string
+	listTo     string
+	listMonth  string
+	listFormat string
+	listFilter string
+	listSort   string
+	listIds    bool
+)
+
+// dayOutput is the representation of a day in the structured formats.
+type dayOutput struct {
+	Date string      `json:"date" yaml:"date"`
+	Logs []model.Log `json:"logs" yaml:"items"`
+}
+
+// logFilter keeps the logs matching all its conditions.
+type logFilter struct {
+	marks     map[model.Category]bool
+	important *bool
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the entries of a day, a range of days or a month",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to, err := listRange()
+		if err != nil {
+			return err
+		}
+		filter, err := parseFilter(listFilter)
+		if err != nil {
+			return err
+		}
+		if listSort != "" && listSort != "important" {
+			return fmt.Errorf("unknown sort %q", listSort)
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+
+		var days []dayOutput
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			dailyLog, err := m.ReadDay(date)
+			if err != nil {
+				return err
+			}
+			logs := filter.apply(dailyLog.Logs)
+			if listSort == "important" {
+				sortImportantFirst(logs)
+			}
+			if len(logs) == 0 && !from.Equal(to) {
+				continue
+			}
+			days = append(days, dayOutput{Date: date.Format(dateLayout), Logs: logs})
+		}
+		return printDays(cmd.OutOrStdout(), days, listFormat, listIds)
+	},
+}
+
+// listRange returns the first and last day requested by the flags.
+func listRange() (time.Time, time.Time, error) {
+	switch {
+	case listMonth != "":
+		if listDate != "" || listFrom != "" || listTo != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--month can't be used with --date, --from or --to")
+		}
+		month, err := time.ParseInLocation(monthLayout, listMonth, time.Local)
+		if err != nil {
+			return month, month, fmt.Errorf("invalid month %q, expected YYYY-MM", listMonth)
+		}
+		return month, month.AddDate(0, 1, -1), nil
+	case listFrom != "" || listTo != "":
+		if listDate != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--date can't be used with --from or --to")
+		}
+		from, err := parseDate(listFrom)
+		if err != nil {
+			return from, from, err
+		}
+		to, err := parseDate(listTo)
+		if err != nil {
+			return from, to, err
+		}
+		from, to = startOfDay(from), startOfDay(to)
+		if from.After(to) {
+			return from, to, fmt.Errorf("--from is after --to")
+		}
+		return from, to, nil
+	default:
+		date, err := parseDate(listDate)
+		date = startOfDay(date)
+		return date, date, err
+	}
+}
+
+func startOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// parseFilter parses a comma separated list of conditions like
+// "mark=task|event,important".
+func parseFilter(value string) (logFilter, error) {
+	filter := logFilter{}
+	for _, term := range strings.Split(value, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		key, val := term, ""
+		if i := strings.Index(term, "="); i >= 0 {
+			key, val = term[:i], term[i+1:]
+		}
+		switch key {
+		case "mark":
+			if filter.marks == nil {
+				filter.marks = map[model.Category]bool{}
+			}
+			for _, mark := range strings.Split(val, "|") {
+				category := model.Category(mark)
+				if !category.IsValid() {
+					return filter, fmt.Errorf("unknown mark %q", mark)
+				}
+				filter.marks[category] = true
+			}
+		case "important":
+			important := val == "" || val == "true"
+			if val != "" && val != "true" && val != "false" {
+				return filter, fmt.Errorf("invalid value for important %q", val)
+			}
+			filter.important = &important
+		default:
+			return filter, fmt.Errorf("unknown filter %q", key)
+		}
+	}
+	return filter, nil
+}
+
+func (f logFilter) match(log model.Log) bool {
+	if f.marks != nil && !f.marks[log.Mark] {
+		return false
+	}
+	if f.important != nil && log.Important != *f.important {
+		return false
+	}
+	return true
+}
+
+// apply returns the logs that match the filter, a log is kept as well when
+// any of its sub logs matches.
+func (f logFilter) apply(logs []model.Log) []model.Log {
+	filtered := []model.Log{}
+	for _, log := range logs {
+		var subLogs []model.Log
+		if log.SubLogs != nil {
+			subLogs = f.apply(*log.SubLogs)
+		}
+		if !f.match(log) && len(subLogs) == 0 {
+			continue
+		}
+		log.SubLogs = nil
+		if len(subLogs) > 0 {
+			log.SubLogs = &subLogs
+		}
+		filtered = append(filtered, log)
+	}
+	return filtered
+}
+
+// sortImportantFirst moves the important logs before their siblings, at every
+// level, keeping the order of the rest.
+func sortImportantFirst(logs []model.Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Important && !logs[j].Important
+	})
+	for _, log := range logs {
+		if log.SubLogs != nil {
+			sortImportantFirst(*log.SubLogs)
+		}
+	}
+}
+
+func printDays(w io.Writer, days []dayOutput, format string, ids bool) error {
+	switch format {
+	case "text":
+		for i, day := range days {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, dayTitle(day.Date))
+			printTextLogs(w, day.Logs, 0, ids)
+		}
+	case "markdown":
+		for i, day := range days {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "## %v\n\n", dayTitle(day.Date))
+			printMarkdownLogs(w, day.Logs, 0)
+		}
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(days)
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		defer encoder.Close()
+		return encoder.Encode(days)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	return nil
+}
+
+func dayTitle(date string) string {
+	day, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return date
+	}
+	return fmt.Sprintf("%v %v", date, utils.ToShortString(day.Weekday()))
+}
+
+func printTextLogs(w io.Writer, logs []model.Log, depth int, ids bool) {
+	for _, log := range logs {
+		if ids {
+			fmt.Fprintf(w, "%v\t", log.Id)
+		}
+		fmt.Fprintf(w, "%v%v\n", strings.Repeat("  ", depth), formatLog(log))
+		if log.SubLogs != nil {
+			printTextLogs(w, *log.SubLogs, depth+1, ids)
+		}
+	}
+}
+
+func printMarkdownLogs(w io.Writer, logs []model.Log, depth int) {
+	for _, log := range logs {
+		name := log.Name
+		if log.Important {
+			name = fmt.Sprintf("**%v**", name)
+		}
+		switch log.Mark {
+		case model.Task:
+			name = "[ ] " + name
+		case model.Complete:
+			name = "[x] " + name
+		case model.Irrelevant:
+			name = fmt.Sprintf("~~%v~~", name)
+		case model.Note:
+		default:
+			name = fmt.Sprintf("%c %v", log.Mark.Print(), name)
+		}
+		fmt.Fprintf(w, "%v- %v\n", strings.Repeat("  ", depth), name)
+		if log.SubLogs != nil {
+			printMarkdownLogs(w, *log.SubLogs, depth+1)
+		}
+	}
+}
+
+func init() {
+	flags := listCmd.Flags()
+	flags.StringVar(&listDate, "date", "", "day to print as YYYY-MM-DD (default today)")
+	flags.StringVar(&listFrom, "from", "", "first day of the range as YYYY-MM-DD")
+	flags.StringVar(&listTo, "to", "", "last day of the range as YYYY-MM-DD (default today)")
+	flags.StringVar(&listMonth, "month", "", "month to print as YYYY-MM")
+	flags.StringVarP(&listFormat, "format", "f", "text", "output format: text, json, yaml or markdown")
+	flags.StringVar(&listFilter, "filter", "", "conditions like mark=task|event,important")
+	flags.StringVar(&listSort, "sort", "", "order of the entries: important puts the important ones first")
+	flags.BoolVar(&listIds, "ids", false, "print the entry ids in the text format")
+	rootCmd.AddCommand(listCmd)
+}
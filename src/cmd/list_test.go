@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterKeepsParentsOfMatches(t *testing.T) {
+	parent := model.NewLog("parent", model.Note)
+	parent.AppendNewSubLog("child task", model.Task)
+	parent.AppendNewSubLog("child note", model.Note)
+	important := model.NewLog("important", model.Task)
+	important.Important = true
+	logs := []model.Log{parent, important, model.NewLog("event", model.Event)}
+
+	filter, err := parseFilter("mark=task")
+	assert.Nil(t, err)
+	filtered := filter.apply(logs)
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, "parent", filtered[0].Name)
+	assert.Len(t, *filtered[0].SubLogs, 1)
+	assert.Equal(t, "child task", (*filtered[0].SubLogs)[0].Name)
+	assert.Len(t, *logs[0].SubLogs, 2)
+
+	filter, err = parseFilter("mark=task|event,important=false")
+	assert.Nil(t, err)
+	filtered = filter.apply(logs)
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, "event", filtered[1].Name)
+
+	_, err = parseFilter("mark=unknown")
+	assert.NotNil(t, err)
+	_, err = parseFilter("color=red")
+	assert.NotNil(t, err)
+}
+
+func TestSortImportantFirst(t *testing.T) {
+	parent := model.NewLog("parent", model.Note)
+	parent.AppendNewSubLog("child", model.Task)
+	important := model.NewLog("important child", model.Task)
+	important.Important = true
+	parent.AppendSubLog(important)
+	urgent := model.NewLog("urgent", model.Task)
+	urgent.Important = true
+	logs := []model.Log{parent, model.NewLog("event", model.Event), urgent}
+
+	sortImportantFirst(logs)
+	assert.Equal(t, "urgent", logs[0].Name)
+	assert.Equal(t, "parent", logs[1].Name)
+	assert.Equal(t, "event", logs[2].Name)
+	assert.Equal(t, "important child", (*logs[1].SubLogs)[0].Name)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+)
+
+const dateLayout = "2006-01-02"
+
+// parseDate parses a date given in the command line, an empty string is today.
+// Besides YYYY-MM-DD it accepts the relative dates of timeconv.ParseDate, like
+// yesterday or "last friday".
+func parseDate(value string) (time.Time, error) {
+	return timeconv.ParseDate(value, time.Now())
+}
+
+// formatLog returns the log as a line of text with its bullet.
+func formatLog(log model.Log) string {
+	signifier := " "
+	if log.Important {
+		signifier = "*"
+	}
+	text := fmt.Sprintf("%v%c %v", signifier, log.Mark.Print(), log.Name)
+	if moment := log.Moment(); moment != nil {
+		text = fmt.Sprintf("%v %c%v", text, model.MomentPrefix, moment)
+	}
+	return text
+}
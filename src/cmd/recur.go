@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recurRule      string
+	recurMark      string
+	recurStart     string
+	recurTime      string
+	recurImportant bool
+)
+
+var recurCmd = &cobra.Command{
+	Use:   "recur",
+	Short: "Manage the entries added on every day of a rule",
+	Long: `Manage the recurrences, entries added to every day of a rule the first
+time the day is opened. They are stored in the recurrence.yaml file of the
+journal, that can be edited by hand.
+
+The rules are daily, weekdays, "weekly on monday,friday", "monthly on day 15",
+"monthly on last" or an RRULE with FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL,
+BYDAY, BYMONTHDAY and UNTIL, like "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO".`,
+}
+
+var recurAddCmd = &cobra.Command{
+	Use:   "add <text>",
+	Short: "Add a recurrence",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start, err := parseDate(recurStart)
+		if err != nil {
+			return err
+		}
+		recurrence := model.NewRecurrence(recurRule, strings.Join(args, " "), model.Category(recurMark), start)
+		recurrence.Time = recurTime
+		recurrence.Important = recurImportant
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		if _, err := m.AddRecurrence(recurrence); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", recurrence.Id, formatRecurrence(recurrence))
+		return nil
+	},
+}
+
+var recurListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the recurrences",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		recurrenceLog, err := m.ReadRecurrences()
+		if err != nil {
+			return err
+		}
+		for _, recurrence := range recurrenceLog.Entries {
+			fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", recurrence.Id, formatRecurrence(recurrence))
+		}
+		return nil
+	},
+}
+
+var recurDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete a recurrence, the entries already added are kept",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		_, err = m.DeleteRecurrence(args[0])
+		return err
+	},
+}
+
+func formatRecurrence(recurrence model.Recurrence) string {
+	log := model.Log{Name: recurrence.Name, Mark: recurrence.Mark, Important: recurrence.Important}
+	text := fmt.Sprintf("%v (%v from %v", formatLog(log), recurrence.Rule, recurrence.Start)
+	if recurrence.Time != "" {
+		text = fmt.Sprintf("%v at %v", text, recurrence.Time)
+	}
+	return text + ")"
+}
+
+func init() {
+	flags := recurAddCmd.Flags()
+	flags.StringVar(&recurRule, "rule", "daily", "days of the entry")
+	flags.StringVar(&recurMark, "mark", string(model.Task), "mark of the entry, task, note or event")
+	flags.StringVar(&recurStart, "start", "", "first day of the rule (default today)")
+	flags.StringVar(&recurTime, "time", "", "time of the entry as HH:MM")
+	flags.BoolVar(&recurImportant, "important", false, "mark the entries as important")
+	recurCmd.AddCommand(recurAddCmd, recurListCmd, recurDeleteCmd)
+	rootCmd.AddCommand(recurCmd)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreGeneration int
+	restoreList       bool
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <date>",
+	Short: "Restore a previous version of a day",
+	Long: `Restore a previous version of a day from its backups.
+
+The generation 1 is the most recent backup. The replaced version becomes the
+generation 1, so restoring it again undoes the restore.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDate(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		if restoreList {
+			backups, err := m.Backups(date)
+			if err != nil {
+				return err
+			}
+			for _, backup := range backups {
+				fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", backup.Generation, backup.ModTime.Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		}
+		dailyLog, err := m.RestoreDay(date, restoreGeneration)
+		if err != nil {
+			return err
+		}
+		printTextLogs(cmd.OutOrStdout(), dailyLog.Logs, 0, false)
+		return nil
+	},
+}
+
+func init() {
+	restoreCmd.Flags().IntVarP(&restoreGeneration, "generation", "g", 1, "backup to restore, 1 is the most recent")
+	restoreCmd.Flags().BoolVar(&restoreList, "list", false, "list the backups of the day")
+	rootCmd.AddCommand(restoreCmd)
+}
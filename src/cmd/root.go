@@ -1,47 +1,133 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	stdlog "log"
+	"os"
+	"path/filepath"
+
 	"github.com/apoloa/bjournal/src/api"
+	"github.com/apoloa/bjournal/src/config"
+	"github.com/apoloa/bjournal/src/scheduler"
 	"github.com/apoloa/bjournal/src/service"
 	"github.com/apoloa/bjournal/src/view"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
-	"os"
-	"path"
-	"path/filepath"
+)
+
+var (
+	configPath string
+	journalDir string
+	port       int
+	editor     string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "bj",
 	Short: "Bullet Journal application",
 	Long:  `A CLI for the Bullet Journal`,
-	Run: func(cmd *cobra.Command, args []string) {
-		mod := os.O_CREATE | os.O_APPEND | os.O_WRONLY
-		executablePath, err := os.Executable()
+	// Errors are printed once by Execute.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
 		if err != nil {
-			panic(err)
+			return err
 		}
-		exePath := filepath.Dir(executablePath)
-		mainPath := path.Join(exePath, "main.log")
-		file, err := os.OpenFile(mainPath, mod, 0777)
+		m, err := newLogService(cfg)
 		if err != nil {
-			log.Printf("Error %v \n", err)
-			log.Fatal()
+			return err
+		}
+		if err := setupLogger(config.LogPath()); err != nil {
+			return err
 		}
-		log.Logger = log.Output(zerolog.ConsoleWriter{Out: file})
-
-		m := service.NewLogService("/Users/apoloalcaide/Developer/Journal")
 
-		router := api.NewRouter(8778, m)
-		router.Init()
-		go router.Start()
+		if cfg.Port != 0 {
+			router := api.NewRouter(cfg.Port, m)
+			router.Init()
+			go router.Start()
+		}
 
-		app := view.NewApp(m)
+		if err := m.StartSession(); err != nil {
+			return err
+		}
+		app := view.NewApp(m).SetMigrationWarning(cfg.MigrationWarning)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go m.Watch(ctx, service.DefaultWatchInterval, app.DayChanged)
+		if len(cfg.Hooks) > 0 {
+			go scheduler.NewScheduler(m, cfg.Hooks).Run(ctx, scheduler.DefaultInterval)
+		}
 		app.Show()
+		return nil
 	},
 }
 
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&configPath, "config", "", "config file (default $XDG_CONFIG_HOME/bjournal/config.yaml)")
+	flags.StringVar(&journalDir, "journal-dir", "", "directory where the journal is stored")
+	flags.IntVar(&port, "port", 0, "port of the local API, 0 disables it")
+	flags.StringVar(&editor, "editor", "", "editor used to open the index items")
+}
+
+// loadConfig reads the config and applies the environment and the flag
+// overrides.
+func loadConfig(cmd *cobra.Command) (config.Config, error) {
+	var cfg config.Config
+	var err error
+	if configPath != "" {
+		cfg, err = config.LoadFile(configPath)
+		if err == nil {
+			err = cfg.ApplyEnv()
+		}
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		return cfg, err
+	}
+	flags := cmd.Flags()
+	if flags.Changed("journal-dir") {
+		cfg.JournalDir = journalDir
+	}
+	if flags.Changed("port") {
+		cfg.Port = port
+	}
+	if flags.Changed("editor") {
+		cfg.Editor = editor
+	}
+	return cfg, cfg.Validate()
+}
+
+// newLogService creates the journal directory if needed and returns the
+// service to manage it.
+func newLogService(cfg config.Config) (*service.LogService, error) {
+	if err := os.MkdirAll(cfg.JournalDir, 0755); err != nil {
+		return nil, err
+	}
+	return service.NewLogService(cfg.JournalDir).
+		SetEditor(cfg.Editor).
+		SetBackups(cfg.Backups), nil
+}
+
+// setupLogger sends the logs to the file to keep the terminal of the UI
+// clean, the other commands log to the standard error.
+func setupLogger(logPath string) error {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("creating the log directory: %w", err)
+	}
+	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening the log file: %w", err)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: file})
+	stdlog.SetOutput(file)
+	return nil
+}
+
 func Execute() {
 	cobra.CheckErr(rootCmd.Execute())
 }
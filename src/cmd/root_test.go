@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/config"
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigFileWithEnv(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "config.yaml")
+	assert.Nil(t, os.WriteFile(p, []byte("journalDir: "+dir+"\nport: 9000\neditor: nano\n"), 0644))
+	t.Setenv(config.EnvJournalDir, "")
+	t.Setenv(config.EnvPort, "9100")
+	t.Setenv(config.EnvEditor, "nvim")
+	configPath = p
+	defer func() { configPath = "" }()
+
+	cfg, err := loadConfig(rootCmd)
+	assert.Nil(t, err)
+	assert.Equal(t, 9100, cfg.Port)
+	assert.Equal(t, "nvim", cfg.Editor)
+}
+
+func TestSetupLoggerFailsWithoutExiting(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, os.WriteFile(file, nil, 0644))
+	assert.NotNil(t, setupLogger(filepath.Join(file, "bjournal", "main.log")))
+}
+
+func TestListHasTheRecurringEntries(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv(config.EnvJournalDir, dir)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	_, err := service.NewLogService(dir).AddRecurrence(model.NewRecurrence("daily", "Water plants", model.Task, yesterday.AddDate(0, 0, -7)))
+	assert.Nil(t, err)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"list", "--date", yesterday.Format(dateLayout)})
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		listDate = ""
+	}()
+	assert.Nil(t, rootCmd.Execute())
+	assert.Contains(t, out.String(), "Water plants")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule <id> <month|date>",
+	Short: "Schedule a task in the future log",
+	Long: `Schedule a task in the future log and mark it as scheduled.
+
+A task scheduled for a month shows up in the daily log of the first day of the
+month, and one scheduled for a date in the daily log of that date. The month
+is written as YYYY-MM and the date like in the --date flags.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, wholeMonth, err := timeconv.ParseMonthOrDate(args[1], time.Now())
+		if err != nil {
+			return err
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		if _, err := m.ScheduleLog(args[0], date, wholeMonth); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%v\t%v\n", args[0], args[1])
+		return nil
+	},
+}
+
+var futureCmd = &cobra.Command{
+	Use:   "future [year]",
+	Short: "Print the future log of a year",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year := time.Now().Year()
+		if len(args) == 1 {
+			var err error
+			if year, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("invalid year %q", args[0])
+			}
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		futureLog, err := m.ReadFuture(year)
+		if err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		for _, entry := range futureLog.Entries {
+			date := entry.Date(year, time.Local)
+			when := date.Format(monthLayout)
+			if entry.Day != 0 {
+				when = date.Format(dateLayout)
+			}
+			fmt.Fprintf(w, "%-10v %v\n", when, formatLog(entry.Log))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd, futureCmd)
+}
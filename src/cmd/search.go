@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	searchFormat string
+	searchIds    bool
+)
+
+// resultOutput is the representation of a search result in the structured
+// formats.
+type resultOutput struct {
+	Date string    `json:"date" yaml:"date"`
+	Log  model.Log `json:"entry" yaml:"entry"`
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the entries of the whole journal",
+	Long: `Search the entries of the whole journal, the most recent first.
+
+The query is a list of terms that must match:
+
+  mark:task           mark of the entry, repeat it to match any of them
+  important:true      important or not important entries
+  text:"invoice"      text in the entry or its linked file, like a bare word
+  after:2026-09-01    entries of that day or later
+  before:yesterday    entries of that day or earlier
+  #tag                entries with the tag`,
+	Example: `  bj search 'mark:task important:true text:"invoice" after:2026-09-01 #work'`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, err := search.Parse(strings.Join(args, " "), time.Now())
+		if err != nil {
+			return err
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		results, err := m.Search(query)
+		if err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		outputs := make([]resultOutput, len(results))
+		for i, result := range results {
+			outputs[i] = resultOutput{Date: result.Date.Format(dateLayout), Log: result.Log}
+		}
+		switch searchFormat {
+		case "text":
+			for _, output := range outputs {
+				if searchIds {
+					fmt.Fprintf(w, "%v\t", output.Log.Id)
+				}
+				fmt.Fprintf(w, "%v %v\n", dayTitle(output.Date), formatLog(output.Log))
+			}
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(outputs)
+		case "yaml":
+			encoder := yaml.NewEncoder(w)
+			defer encoder.Close()
+			return encoder.Encode(outputs)
+		default:
+			return fmt.Errorf("unknown format %q", searchFormat)
+		}
+		return nil
+	},
+}
+
+func init() {
+	searchCmd.Flags().StringVarP(&searchFormat, "format", "f", "text", "output format: text, json or yaml")
+	searchCmd.Flags().BoolVar(&searchIds, "ids", false, "print the entry ids in the text format")
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags [#tag|@context]",
+	Short: "Print the tags of the journal or the entries with a tag",
+	Long: `Print every #tag and @context written in the entries of the journal with
+the number of entries that have it. With a tag or a context, print those
+entries, the most recent days first.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		if len(args) == 1 {
+			results, err := m.TaggedLogs(args[0])
+			if err != nil {
+				return err
+			}
+			for _, result := range results {
+				fmt.Fprintf(w, "%v\t%v %v\n", result.Log.Id, result.Date.Format(dateLayout), formatLog(result.Log))
+			}
+			return nil
+		}
+		tags, err := m.Tags()
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			fmt.Fprintf(w, "%v\t%d\n", tag.Tag, tag.Count)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var undoRedo bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last change of the journal",
+	Long: `Undo the last change of the journal made by the UI, the API or the CLI
+since the UI was last opened. --redo applies again the last undone change.
+
+A change is not undone when its files were modified by something else since.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		m, err := newLogService(cfg)
+		if err != nil {
+			return err
+		}
+		action, done := m.Undo, "Undone"
+		if undoRedo {
+			action, done = m.Redo, "Redone"
+		}
+		command, err := action()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%v: %v (%v)\n", done, command.Name, command.Time.Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoRedo, "redo", false, "redo the last undone change")
+	rootCmd.AddCommand(undoCmd)
+}
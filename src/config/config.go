@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	appName    = "bjournal"
+	configFile = "config.yaml"
+
+	defaultPort             = 8778
+	defaultMigrationWarning = 3
+
+	// DefaultBackups is the number of previous versions kept for each file.
+	DefaultBackups = 5
+
+	// Environment variables that override the values of the config file.
+	EnvConfig     = "BJOURNAL_CONFIG"
+	EnvJournalDir = "BJOURNAL_JOURNAL_DIR"
+	EnvPort       = "BJOURNAL_PORT"
+	EnvEditor     = "BJOURNAL_EDITOR"
+)
+
+// The moments that fire the hooks.
+const (
+	// HookStart fires when an event starts.
+	HookStart = "start"
+	// HookOverdue fires when a task is still open after it was due.
+	HookOverdue = "overdue"
+)
+
+// Config holds the user settings of the application.
+type Config struct {
+	// JournalDir is the directory where the daily logs are stored.
+	JournalDir string `json:"journal_dir" yaml:"journalDir"`
+	// Port is the port of the local API, 0 disables the API.
+	Port int `json:"port" yaml:"port"`
+	// Editor is the command used to open the index items.
+	Editor string `json:"editor" yaml:"editor"`
+	// Backups is the number of previous versions kept for each file, they are
+	// stored in the .bjournal/backups directory of the journal.
+	Backups int `json:"backups" yaml:"backups"`
+	// MigrationWarning is the number of migrations after which an entry is
+	// highlighted as pushed forward too many times, 0 disables it.
+	MigrationWarning int `json:"migration_warning" yaml:"migrationWarning"`
+	// Hooks are run by the scheduler when events start or tasks go overdue.
+	Hooks []Hook `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+}
+
+// Hook is an action run for an entry, exactly one of Command, Fifo and Notify
+// is set.
+type Hook struct {
+	// On is the moment that fires the hook, HookStart or HookOverdue.
+	On string `json:"on" yaml:"on"`
+	// Before fires the start hooks in advance, like 10m.
+	Before time.Duration `json:"before,omitempty" yaml:"before,omitempty"`
+	// Command is a shell command, the entry is in the BJ_* environment
+	// variables.
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	// Fifo is the path of a named pipe where a line is written for the entry.
+	Fifo string `json:"fifo,omitempty" yaml:"fifo,omitempty"`
+	// Notify shows a desktop notification with notify-send.
+	Notify bool `json:"notify,omitempty" yaml:"notify,omitempty"`
+}
+
+// Validate checks the hook has a known moment and a single action.
+func (h Hook) Validate() error {
+	if h.On != HookStart && h.On != HookOverdue {
+		return fmt.Errorf("invalid hook %q, expected %v or %v", h.On, HookStart, HookOverdue)
+	}
+	if h.Before < 0 {
+		return fmt.Errorf("invalid hook advance %v", h.Before)
+	}
+	actions := 0
+	for _, set := range []bool{strings.TrimSpace(h.Command) != "", strings.TrimSpace(h.Fifo) != "", h.Notify} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("the %v hook needs exactly one of command, fifo or notify", h.On)
+	}
+	return nil
+}
+
+// Default returns the configuration used when nothing else is provided.
+func Default() Config {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	return Config{
+		JournalDir:       filepath.Join(dataHome(), appName),
+		Port:             defaultPort,
+		Editor:           editor,
+		Backups:          DefaultBackups,
+		MigrationWarning: defaultMigrationWarning,
+	}
+}
+
+// Path returns the path of the user config file, honoring $BJOURNAL_CONFIG
+// and $XDG_CONFIG_HOME.
+func Path() string {
+	if p := os.Getenv(EnvConfig); p != "" {
+		return p
+	}
+	return filepath.Join(configHome(), appName, configFile)
+}
+
+// searchPaths returns the candidate config files ordered by priority.
+func searchPaths() []string {
+	paths := []string{Path()}
+	if os.Getenv(EnvConfig) != "" {
+		return paths
+	}
+	dirs := os.Getenv("XDG_CONFIG_DIRS")
+	if dirs == "" {
+		dirs = "/etc/xdg"
+	}
+	for _, dir := range filepath.SplitList(dirs) {
+		if dir != "" {
+			paths = append(paths, filepath.Join(dir, appName, configFile))
+		}
+	}
+	return paths
+}
+
+// LogPath returns the file of the logs of the UI, in the XDG state directory.
+func LogPath() string {
+	return filepath.Join(stateHome(), appName, "main.log")
+}
+
+// Load reads the first config file found in the XDG paths and applies the
+// environment overrides on top of it. A missing file is not an error.
+func Load() (Config, error) {
+	for _, p := range searchPaths() {
+		cfg, err := LoadFile(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return cfg, err
+		}
+		return cfg, cfg.ApplyEnv()
+	}
+	cfg := Default()
+	return cfg, cfg.ApplyEnv()
+}
+
+// LoadFile reads the config file in the given path over the default values,
+// without the environment overrides of ApplyEnv.
+func LoadFile(p string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing config %v: %w", p, err)
+	}
+	return cfg, nil
+}
+
+// ApplyEnv overrides the values of the config with the environment variables.
+func (c *Config) ApplyEnv() error {
+	if v := os.Getenv(EnvJournalDir); v != "" {
+		c.JournalDir = v
+	}
+	if v := os.Getenv(EnvPort); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %v %q: %w", EnvPort, v, err)
+		}
+		c.Port = port
+	}
+	if v := os.Getenv(EnvEditor); v != "" {
+		c.Editor = v
+	}
+	return nil
+}
+
+// Validate checks the config and expands the journal directory.
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.JournalDir) == "" {
+		return errors.New("the journal directory is empty")
+	}
+	c.JournalDir = expandHome(c.JournalDir)
+	info, err := os.Stat(c.JournalDir)
+	if err == nil && !info.IsDir() {
+		return fmt.Errorf("the journal directory %v is not a directory", c.JournalDir)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("the journal directory %v: %w", c.JournalDir, err)
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid port %v", c.Port)
+	}
+	if strings.TrimSpace(c.Editor) == "" {
+		return errors.New("the editor is empty")
+	}
+	if c.Backups < 0 {
+		return fmt.Errorf("invalid number of backups %v", c.Backups)
+	}
+	if c.MigrationWarning < 0 {
+		return fmt.Errorf("invalid number of migrations %v", c.MigrationWarning)
+	}
+	for i := range c.Hooks {
+		if err := c.Hooks[i].Validate(); err != nil {
+			return err
+		}
+		c.Hooks[i].Fifo = expandHome(c.Hooks[i].Fifo)
+	}
+	return nil
+}
+
+// ToBytes returns the config serialized as YAML.
+func (c Config) ToBytes() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// Init writes the default config in the given path.
+func Init(p string, force bool) error {
+	if _, err := os.Stat(p); err == nil && !force {
+		return fmt.Errorf("the config file %v already exists", p)
+	}
+	bytes, err := Default().ToBytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, bytes, 0644)
+}
+
+func expandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, strings.TrimPrefix(p, "~"))
+}
+
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	return expandHome("~/.config")
+}
+
+func stateHome() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return dir
+	}
+	return expandHome("~/.local/state")
+}
+
+func dataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	return expandHome("~/.local/share")
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_CONFIG_DIRS", filepath.Join(dir, "none"))
+	t.Setenv(EnvConfig, "")
+	t.Setenv(EnvJournalDir, "")
+	t.Setenv(EnvPort, "")
+	t.Setenv(EnvEditor, "")
+
+	cfg, err := Load()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultPort, cfg.Port)
+
+	p := filepath.Join(dir, appName, configFile)
+	assert.Equal(t, p, Path())
+	assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+	assert.Nil(t, os.WriteFile(p, []byte("journalDir: /tmp/journal\nport: 9000\neditor: nano\n"), 0644))
+
+	cfg, err = Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/journal", cfg.JournalDir)
+	assert.Equal(t, 9000, cfg.Port)
+	assert.Equal(t, "nano", cfg.Editor)
+
+	t.Setenv(EnvPort, "9100")
+	t.Setenv(EnvEditor, "nvim")
+	cfg, err = Load()
+	assert.Nil(t, err)
+	assert.Equal(t, 9100, cfg.Port)
+	assert.Equal(t, "nvim", cfg.Editor)
+
+	t.Setenv(EnvPort, "port")
+	_, err = Load()
+	assert.NotNil(t, err)
+}
+
+func TestLoadFileWithEnv(t *testing.T) {
+	p := filepath.Join(t.TempDir(), configFile)
+	assert.Nil(t, os.WriteFile(p, []byte("journalDir: /tmp/journal\nport: 9000\neditor: nano\n"), 0644))
+	t.Setenv(EnvJournalDir, "/tmp/other")
+	t.Setenv(EnvPort, "9100")
+	t.Setenv(EnvEditor, "")
+
+	cfg, err := LoadFile(p)
+	assert.Nil(t, err)
+	assert.Equal(t, 9000, cfg.Port)
+	assert.Nil(t, cfg.ApplyEnv())
+	assert.Equal(t, "/tmp/other", cfg.JournalDir)
+	assert.Equal(t, 9100, cfg.Port)
+	assert.Equal(t, "nano", cfg.Editor)
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{JournalDir: dir, Port: 8778, Editor: "vi"}
+	assert.Nil(t, cfg.Validate())
+
+	cfg.Port = 70000
+	assert.NotNil(t, cfg.Validate())
+
+	file := filepath.Join(dir, "file")
+	assert.Nil(t, os.WriteFile(file, nil, 0644))
+	cfg = Config{JournalDir: file, Port: 8778, Editor: "vi"}
+	assert.NotNil(t, cfg.Validate())
+
+	cfg = Config{JournalDir: dir, Port: 8778}
+	assert.NotNil(t, cfg.Validate())
+}
+
+func TestLoadHooks(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, configFile)
+	hooks := "journalDir: " + dir + "\nhooks:\n  - on: start\n    before: 10m\n    notify: true\n  - on: overdue\n    command: echo $BJ_NAME\n"
+	assert.Nil(t, os.WriteFile(p, []byte(hooks), 0644))
+	cfg, err := LoadFile(p)
+	assert.Nil(t, err)
+	assert.Nil(t, cfg.Validate())
+	assert.Len(t, cfg.Hooks, 2)
+	assert.Equal(t, 10*time.Minute, cfg.Hooks[0].Before)
+	assert.True(t, cfg.Hooks[0].Notify)
+	assert.Equal(t, "echo $BJ_NAME", cfg.Hooks[1].Command)
+
+	assert.NotNil(t, Hook{On: "later", Notify: true}.Validate())
+	assert.NotNil(t, Hook{On: HookStart}.Validate())
+	assert.NotNil(t, Hook{On: HookStart, Notify: true, Fifo: "/tmp/bj"}.Validate())
+}
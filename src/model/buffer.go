@@ -24,25 +24,35 @@ type (
 		// BufferActive indicates the buff activity changed.
 		BufferActive(state bool)
 	}
+
+	// SuggestionFunc returns the completions of the text, the characters
+	// that follow it.
+	SuggestionFunc func(text string) []string
 )
 
 // CmdBuff represents user command input.
 type CmdBuff struct {
 	buff       []rune
 	suggestion string
-	listeners  []BuffWatcher
-	hotKey     rune
-	active     bool
-	cancel     context.CancelFunc
-	mx         sync.RWMutex
+	// suggestions are the completions of the text, suggestionIndex is the
+	// shown one.
+	suggestions     []string
+	suggestionIndex int
+	suggestionFn    SuggestionFunc
+	listeners       []BuffWatcher
+	hotKey          rune
+	active          bool
+	cancel          context.CancelFunc
+	mx              sync.RWMutex
 }
 
 // NewCmdBuff returns a new command buffer.
 func NewCmdBuff(key rune) *CmdBuff {
 	return &CmdBuff{
-		hotKey:    key,
-		buff:      make([]rune, 0, maxBuff),
-		listeners: []BuffWatcher{},
+		hotKey:          key,
+		buff:            make([]rune, 0, maxBuff),
+		suggestionIndex: -1,
+		listeners:       []BuffWatcher{},
 	}
 }
 
@@ -72,9 +82,60 @@ func (c *CmdBuff) GetSuggestion() string {
 	return c.suggestion
 }
 
+// SetSuggestionFn sets the function that completes the text while it is
+// typed.
+func (c *CmdBuff) SetSuggestionFn(fn SuggestionFunc) {
+	c.suggestionFn = fn
+}
+
+// suggest refreshes the completions of the text.
+func (c *CmdBuff) suggest() {
+	c.ClearSuggestions()
+	if c.suggestionFn == nil || len(c.buff) == 0 {
+		return
+	}
+	c.suggestions = c.suggestionFn(string(c.buff))
+	if len(c.suggestions) > 0 {
+		c.suggestionIndex = 0
+		c.suggestion = c.suggestions[0]
+	}
+}
+
+// CurrentSuggestion returns the shown completion, false if there is none.
+func (c *CmdBuff) CurrentSuggestion() (string, bool) {
+	return c.suggestion, c.suggestionIndex >= 0
+}
+
+// NextSuggestion shows the next completion.
+func (c *CmdBuff) NextSuggestion() (string, bool) {
+	return c.moveSuggestion(1)
+}
+
+// PrevSuggestion shows the previous completion.
+func (c *CmdBuff) PrevSuggestion() (string, bool) {
+	return c.moveSuggestion(-1)
+}
+
+func (c *CmdBuff) moveSuggestion(offset int) (string, bool) {
+	if len(c.suggestions) == 0 {
+		return "", false
+	}
+	c.suggestionIndex = (c.suggestionIndex + offset + len(c.suggestions)) % len(c.suggestions)
+	c.suggestion = c.suggestions[c.suggestionIndex]
+	return c.suggestion, true
+}
+
+// ClearSuggestions clears out the completions.
+func (c *CmdBuff) ClearSuggestions() {
+	c.suggestions = nil
+	c.suggestionIndex = -1
+	c.suggestion = ""
+}
+
 // SetText initializes the buffer with a command.
 func (c *CmdBuff) SetText(text string) {
 	c.buff = []rune(text)
+	c.ClearSuggestions()
 	c.fireBufferCompleted()
 }
 
@@ -83,6 +144,7 @@ func (c *CmdBuff) Add(r rune) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 	c.buff = append(c.buff, r)
+	c.suggest()
 	c.fireBufferChanged()
 	if c.cancel != nil {
 		return
@@ -109,6 +171,7 @@ func (c *CmdBuff) Delete() {
 		return
 	}
 	c.buff = c.buff[:len(c.buff)-1]
+	c.suggest()
 	c.fireBufferChanged()
 	if c.cancel != nil {
 		return
@@ -133,6 +196,7 @@ func (c *CmdBuff) ClearText(fire bool) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 	c.buff = make([]rune, 0, maxBuff)
+	c.ClearSuggestions()
 	if fire {
 		c.fireBufferCompleted()
 	}
@@ -16,6 +16,19 @@ const (
 	Event      Category = "event"
 )
 
+// Categories contains every known category.
+var Categories = []Category{Task, Complete, Irrelevant, Migrated, Scheduled, Note, Event}
+
+// IsValid checks if the category is a known one.
+func (c Category) IsValid() bool {
+	for _, category := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
 func (c Category) Print() rune {
 	switch {
 	case c == Task:
@@ -5,22 +5,32 @@ import (
 	"path"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
 	"gopkg.in/yaml.v3"
 )
 
 type DailyLog struct {
-	key      string    `yaml:"-"`
-	basePath string    `yaml:"-"`
-	Date     time.Time `json:"-" yaml:"-"`
-	Logs     []Log     `json:"logs" yaml:"items"`
+	key       string    `yaml:"-"`
+	basePath  string    `yaml:"-"`
+	idsFilled bool      `yaml:"-"`
+	Date      time.Time `json:"-" yaml:"-"`
+	Logs      []Log     `json:"logs" yaml:"items"`
+	// Recurrences are the ids of the recurrences already added to the day,
+	// so they are added only once.
+	Recurrences []string `json:"recurrences,omitempty" yaml:"recurrences,omitempty"`
+	// Habits are the names of the habits done on the day.
+	Habits []string `json:"habits,omitempty" yaml:"habits,omitempty"`
 }
 
 func NewDailyLog(date, basePath string) DailyLog {
+	dateTime, err := timeconv.StringToDayTime(date)
+	if err != nil {
+		dateTime = time.Now()
+	}
 	return DailyLog{
 		key:      date,
 		basePath: basePath,
-		Date:     time.Now(),
+		Date:     dateTime,
 		Logs:     []Log{},
 	}
 }
@@ -40,23 +50,89 @@ func (d *DailyLog) fullRead() {
 }
 
 func (d *DailyLog) setParent() {
-	for id, parent := range d.Logs {
-		if parent.SubLogs == nil {
-			continue
+	setParents(d.Logs, nil)
+}
+
+// setParents points the logs and all their sub logs to their parents.
+func setParents(logs []Log, parent *Log) {
+	for i := range logs {
+		logs[i].Parent = parent
+		if logs[i].SubLogs != nil {
+			setParents(*logs[i].SubLogs, &logs[i])
 		}
-		if len(*parent.SubLogs) == 0 {
-			continue
+	}
+}
+
+// fillIds assigns an id to the logs stored without it by older versions.
+func (d *DailyLog) fillIds() {
+	for index := range d.Logs {
+		if d.Logs[index].fillIds() {
+			d.idsFilled = true
 		}
-		for index, _ := range *parent.SubLogs {
-			(*parent.SubLogs)[index].Parent = &d.Logs[id]
+	}
+}
+
+// IdsFilled checks if any log got a new id when the day was read, so the day
+// must be saved to keep the ids.
+func (d *DailyLog) IdsFilled() bool {
+	return d.idsFilled
+}
+
+// Key returns the name of the day file without extension.
+func (d *DailyLog) Key() string {
+	return d.key
+}
+
+// Copy returns a deep copy of the day.
+func (d DailyLog) Copy() DailyLog {
+	logs := make([]Log, len(d.Logs))
+	for i, log := range d.Logs {
+		logs[i] = log.Clone()
+	}
+	d.Logs = logs
+	d.Recurrences = append([]string(nil), d.Recurrences...)
+	d.Habits = append([]string(nil), d.Habits...)
+	d.setParent()
+	return d
+}
+
+// SetLogs replaces the logs of the day, giving an id to the new ones.
+func (d *DailyLog) SetLogs(logs []Log) {
+	d.Logs = logs
+	d.fillIds()
+	d.setParent()
+}
+
+// Remove deletes the log or sub log with the given id.
+func (d *DailyLog) Remove(id string) bool {
+	return removeLog(&d.Logs, id)
+}
+
+// CheckHabit marks the habit as done on the day, or as not done, returning
+// true when that changed the day.
+func (d *DailyLog) CheckHabit(name string, done bool) bool {
+	for i, habit := range d.Habits {
+		if habit == name {
+			if !done {
+				d.Habits = append(d.Habits[:i], d.Habits[i+1:]...)
+			}
+			return !done
 		}
 	}
+	if done {
+		d.Habits = append(d.Habits, name)
+	}
+	return done
 }
 
-func (d *DailyLog) addUUID() {
-	for index, _ := range d.Logs {
-		d.Logs[index].Id = uuid.NewString()
+// Find returns the log or sub log with the given id.
+func (d *DailyLog) Find(id string) *Log {
+	for index := range d.Logs {
+		if found := d.Logs[index].Find(id); found != nil {
+			return found
+		}
 	}
+	return nil
 }
 
 func DailyFrom(from []byte, dateTime time.Time, date string, dir string) (DailyLog, error) {
@@ -70,10 +146,80 @@ func DailyFrom(from []byte, dateTime time.Time, date string, dir string) (DailyL
 	dailyLog.basePath = dir
 	dailyLog.fullRead()
 	dailyLog.setParent()
-	dailyLog.addUUID()
+	dailyLog.fillIds()
+	dailyLog.ParseTags()
 	return dailyLog, nil
 }
 
 func (d *DailyLog) ToBytes() ([]byte, error) {
 	return yaml.Marshal(d)
 }
+
+// findSiblings returns the logs that contain the log with the given id, its
+// parent, nil for the logs of the day, and its index.
+func findSiblings(logs *[]Log, parent *Log, id string) (*[]Log, *Log, int) {
+	for i := range *logs {
+		if (*logs)[i].Id == id {
+			return logs, parent, i
+		}
+		if (*logs)[i].SubLogs != nil {
+			if siblings, parent, index := findSiblings((*logs)[i].SubLogs, &(*logs)[i], id); siblings != nil {
+				return siblings, parent, index
+			}
+		}
+	}
+	return nil, nil, -1
+}
+
+// Move moves the log the offset positions among its siblings, up when it is
+// negative, returning false when it is not possible.
+func (d *DailyLog) Move(id string, offset int) bool {
+	siblings, _, index := findSiblings(&d.Logs, nil, id)
+	if siblings == nil || index+offset < 0 || index+offset >= len(*siblings) {
+		return false
+	}
+	log := (*siblings)[index]
+	if offset < 0 {
+		copy((*siblings)[index+offset+1:index+1], (*siblings)[index+offset:index])
+	} else {
+		copy((*siblings)[index:index+offset], (*siblings)[index+1:index+offset+1])
+	}
+	(*siblings)[index+offset] = log
+	d.setParent()
+	return true
+}
+
+// Indent makes the log, with its sub logs, the last sub log of the previous
+// one, returning false when it is the first one.
+func (d *DailyLog) Indent(id string) bool {
+	siblings, _, index := findSiblings(&d.Logs, nil, id)
+	if siblings == nil || index == 0 {
+		return false
+	}
+	log := (*siblings)[index]
+	*siblings = append((*siblings)[:index], (*siblings)[index+1:]...)
+	(*siblings)[index-1].AppendSubLog(log)
+	d.setParent()
+	return true
+}
+
+// Outdent moves the sub log after its parent, returning false when the log has
+// no parent.
+func (d *DailyLog) Outdent(id string) bool {
+	siblings, parent, index := findSiblings(&d.Logs, nil, id)
+	if siblings == nil || parent == nil {
+		return false
+	}
+	log := (*siblings)[index]
+	log.Parent = nil
+	*siblings = append((*siblings)[:index], (*siblings)[index+1:]...)
+	if len(*siblings) == 0 {
+		parent.SubLogs = nil
+	}
+	parentSiblings, _, parentIndex := findSiblings(&d.Logs, nil, parent.Id)
+	*parentSiblings = append(*parentSiblings, Log{})
+	copy((*parentSiblings)[parentIndex+2:], (*parentSiblings)[parentIndex+1:])
+	(*parentSiblings)[parentIndex+1] = log
+	d.setParent()
+	return true
+}
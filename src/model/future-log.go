@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FutureEntry is a log scheduled for a month, or for a day of it when Day is
+// not zero.
+type FutureEntry struct {
+	Month time.Month `json:"month" yaml:"month"`
+	Day   int        `json:"day,omitempty" yaml:"day,omitempty"`
+	// Source is the key of the day the log was scheduled from.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// Surfaced is true once the log was added to the daily log of its date.
+	Surfaced bool `json:"surfaced" yaml:"surfaced"`
+	Log      Log  `json:"log" yaml:"log"`
+}
+
+// Date returns the date when the entry is due, the first of the month for the
+// entries without day.
+func (e *FutureEntry) Date(year int, location *time.Location) time.Time {
+	day := e.Day
+	if day == 0 {
+		day = 1
+	}
+	return time.Date(year, e.Month, day, 0, 0, 0, 0, location)
+}
+
+// FutureLog contains the logs scheduled for the months of a year.
+type FutureLog struct {
+	key     string        `yaml:"-"`
+	Year    int           `json:"year" yaml:"-"`
+	Entries []FutureEntry `json:"entries" yaml:"items"`
+}
+
+func futureKey(year int) string {
+	return fmt.Sprintf("future.%d", year)
+}
+
+func NewFutureLog(year int) FutureLog {
+	return FutureLog{
+		key:     futureKey(year),
+		Year:    year,
+		Entries: []FutureEntry{},
+	}
+}
+
+func FutureFrom(from []byte, year int) (FutureLog, error) {
+	futureLog := NewFutureLog(year)
+	err := yaml.Unmarshal(from, &futureLog)
+	if err != nil {
+		return futureLog, err
+	}
+	if futureLog.Entries == nil {
+		futureLog.Entries = []FutureEntry{}
+	}
+	for i := range futureLog.Entries {
+		futureLog.Entries[i].Log.fillIds()
+	}
+	return futureLog, nil
+}
+
+// Key returns the name of the future log file without extension.
+func (f *FutureLog) Key() string {
+	return f.key
+}
+
+// Schedule adds the log to the month of the date, or to the day when
+// wholeMonth is false, keeping the entries sorted by date.
+func (f *FutureLog) Schedule(date time.Time, wholeMonth bool, source string, log Log) {
+	entry := FutureEntry{Month: date.Month(), Source: source, Log: log}
+	if !wholeMonth {
+		entry.Day = date.Day()
+	}
+	f.Entries = append(f.Entries, entry)
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		if f.Entries[i].Month != f.Entries[j].Month {
+			return f.Entries[i].Month < f.Entries[j].Month
+		}
+		return f.Entries[i].Day < f.Entries[j].Day
+	})
+}
+
+// Due returns the indexes of the entries not surfaced yet with a date before
+// or equal to the date.
+func (f *FutureLog) Due(date time.Time) []int {
+	var due []int
+	for i := range f.Entries {
+		if !f.Entries[i].Surfaced && !f.Entries[i].Date(f.Year, date.Location()).After(date) {
+			due = append(due, i)
+		}
+	}
+	return due
+}
+
+// Copy returns a deep copy of the future log.
+func (f FutureLog) Copy() FutureLog {
+	entries := make([]FutureEntry, len(f.Entries))
+	for i, entry := range f.Entries {
+		entry.Log = entry.Log.Clone()
+		entries[i] = entry
+	}
+	f.Entries = entries
+	return f
+}
+
+func (f *FutureLog) ToBytes() ([]byte, error) {
+	return yaml.Marshal(f)
+}
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"gopkg.in/yaml.v3"
+)
+
+// Period is the time the check-ins of a habit are counted on.
+type Period string
+
+const (
+	PerDay   Period = "day"
+	PerWeek  Period = "week"
+	PerMonth Period = "month"
+)
+
+// HabitRateDays is the number of days the completion rates are shown for.
+const HabitRateDays = 30
+
+// Habit is something to do a number of times every period, like running 3
+// times a week.
+type Habit struct {
+	Name string `json:"name" yaml:"name"`
+	// Times is the number of check-ins expected every period.
+	Times int    `json:"times" yaml:"times"`
+	Per   Period `json:"per" yaml:"per"`
+}
+
+// NewHabit returns the habit with the target written as daily, weekly,
+// monthly or times/period, like 3/week.
+func NewHabit(name, target string) (Habit, error) {
+	times, per, err := ParseTarget(target)
+	if err != nil {
+		return Habit{}, err
+	}
+	habit := Habit{Name: strings.TrimSpace(name), Times: times, Per: per}
+	return habit, habit.Validate()
+}
+
+// ParseTarget parses daily, weekly, monthly or times/period, like 3/week.
+func ParseTarget(text string) (int, Period, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	switch text {
+	case "daily":
+		return 1, PerDay, nil
+	case "weekly":
+		return 1, PerWeek, nil
+	case "monthly":
+		return 1, PerMonth, nil
+	}
+	count, period, ok := strings.Cut(text, "/")
+	times, err := strconv.Atoi(strings.TrimSpace(count))
+	if !ok || err != nil {
+		return 0, "", fmt.Errorf("invalid target %q, expected daily, weekly, monthly or times/period like 3/week", text)
+	}
+	per := Period(strings.TrimSpace(period))
+	return times, per, Habit{Name: "target", Times: times, Per: per}.Validate()
+}
+
+// Target returns the target as it is parsed by ParseTarget.
+func (h Habit) Target() string {
+	if h.Times == 1 {
+		switch h.Per {
+		case PerDay:
+			return "daily"
+		case PerWeek:
+			return "weekly"
+		case PerMonth:
+			return "monthly"
+		}
+	}
+	return fmt.Sprintf("%d/%v", h.Times, h.Per)
+}
+
+// Validate checks the habit has a name and a target that can be met.
+func (h Habit) Validate() error {
+	if h.Name == "" {
+		return errors.New("the name is empty")
+	}
+	days := 0
+	switch h.Per {
+	case PerDay:
+		days = 1
+	case PerWeek:
+		days = 7
+	case PerMonth:
+		days = 28
+	default:
+		return fmt.Errorf("invalid period %q, expected day, week or month", h.Per)
+	}
+	if h.Times < 1 || h.Times > days {
+		return fmt.Errorf("invalid target %d/%v", h.Times, h.Per)
+	}
+	return nil
+}
+
+// periodStart returns the first day of the period of the date, weeks start on
+// monday.
+func (h Habit) periodStart(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch h.Per {
+	case PerWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case PerMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// nextPeriod returns the first day of the period after the one starting on
+// start.
+func (h Habit) nextPeriod(start time.Time) time.Time {
+	switch h.Per {
+	case PerWeek:
+		return start.AddDate(0, 0, 7)
+	case PerMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// periodCount returns the check-ins of the period starting on start.
+func (h Habit) periodCount(checkIns CheckIns, start time.Time) int {
+	count := 0
+	for day := start; day.Before(h.nextPeriod(start)); day = day.AddDate(0, 0, 1) {
+		if checkIns.Has(day) {
+			count++
+		}
+	}
+	return count
+}
+
+// Streak returns the number of periods in a row, up to the one of today, that
+// met the target. The period of today only adds to the streak once it is met.
+func (h Habit) Streak(checkIns CheckIns, today time.Time) int {
+	streak := 0
+	start := h.periodStart(today)
+	if h.periodCount(checkIns, start) >= h.Times {
+		streak++
+	}
+	for {
+		start = h.periodStart(start.AddDate(0, 0, -1))
+		if h.periodCount(checkIns, start) < h.Times {
+			return streak
+		}
+		streak++
+	}
+}
+
+// CompletionRate returns the part, from 0 to 1, of the check-ins expected in
+// the periods from the one of from to the one of to that were done. The
+// check-ins over the target of a period don't make up for other periods.
+func (h Habit) CompletionRate(checkIns CheckIns, from, to time.Time) float64 {
+	done, expected := 0, 0
+	for start := h.periodStart(from); !start.After(to); start = h.nextPeriod(start) {
+		count := h.periodCount(checkIns, start)
+		if count > h.Times {
+			count = h.Times
+		}
+		done += count
+		expected += h.Times
+	}
+	if expected == 0 {
+		return 0
+	}
+	return float64(done) / float64(expected)
+}
+
+// CheckIns are the days a habit was done, by day key.
+type CheckIns map[string]bool
+
+// Has checks if the habit was done on the day of the date.
+func (c CheckIns) Has(date time.Time) bool {
+	return c[timeconv.TimeToDayString(date)]
+}
+
+// HabitLog contains the habits of the journal.
+type HabitLog struct {
+	Entries []Habit `json:"entries" yaml:"items"`
+}
+
+func HabitsFrom(from []byte) (HabitLog, error) {
+	habitLog := HabitLog{}
+	if err := yaml.Unmarshal(from, &habitLog); err != nil {
+		return habitLog, err
+	}
+	if habitLog.Entries == nil {
+		habitLog.Entries = []Habit{}
+	}
+	return habitLog, nil
+}
+
+// Find returns the habit with the name, ignoring the case.
+func (h *HabitLog) Find(name string) (Habit, bool) {
+	for _, habit := range h.Entries {
+		if strings.EqualFold(habit.Name, strings.TrimSpace(name)) {
+			return habit, true
+		}
+	}
+	return Habit{}, false
+}
+
+// Remove deletes the habit with the name, ignoring the case.
+func (h *HabitLog) Remove(name string) bool {
+	for i, habit := range h.Entries {
+		if strings.EqualFold(habit.Name, strings.TrimSpace(name)) {
+			h.Entries = append(h.Entries[:i], h.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (h *HabitLog) ToBytes() ([]byte, error) {
+	return yaml.Marshal(h)
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHabitTargets(t *testing.T) {
+	for target, expected := range map[string]Habit{
+		"daily":   {Name: "Run", Times: 1, Per: PerDay},
+		"weekly":  {Name: "Run", Times: 1, Per: PerWeek},
+		"3/week":  {Name: "Run", Times: 3, Per: PerWeek},
+		"2/month": {Name: "Run", Times: 2, Per: PerMonth},
+	} {
+		habit, err := NewHabit("Run", target)
+		assert.Nil(t, err, target)
+		assert.Equal(t, expected, habit)
+		assert.Equal(t, target, habit.Target())
+	}
+	for _, target := range []string{"", "hourly", "0/week", "8/week", "2/day", "3/year", "three/week"} {
+		_, err := NewHabit("Run", target)
+		assert.NotNil(t, err, target)
+	}
+	_, err := NewHabit(" ", "daily")
+	assert.NotNil(t, err)
+}
+
+func TestHabitStreakAndRate(t *testing.T) {
+	// Wednesday.
+	today := time.Date(2026, time.October, 21, 0, 0, 0, 0, time.Local)
+	checkIns := func(days ...int) CheckIns {
+		checkIns := CheckIns{}
+		for _, day := range days {
+			checkIns[timeconv.TimeToDayString(today.AddDate(0, 0, -day))] = true
+		}
+		return checkIns
+	}
+
+	daily := Habit{Name: "Read", Times: 1, Per: PerDay}
+	// Today is not done yet, the streak goes on from yesterday.
+	assert.Equal(t, 3, daily.Streak(checkIns(1, 2, 3, 5), today))
+	assert.Equal(t, 4, daily.Streak(checkIns(0, 1, 2, 3, 5), today))
+	assert.Equal(t, 0, daily.Streak(checkIns(2, 3), today))
+	assert.Equal(t, 0.5, daily.CompletionRate(checkIns(0, 1, 3, 5, 6), today.AddDate(0, 0, -9), today))
+
+	weekly := Habit{Name: "Run", Times: 2, Per: PerWeek}
+	// This week has one run, the week before three and the one before two.
+	runs := checkIns(0, 6, 8, 9, 10, 12)
+	assert.Equal(t, 2, weekly.Streak(runs, today))
+	assert.Equal(t, 3, weekly.Streak(checkIns(0, 1, 6, 8, 9, 10, 12), today))
+	// The third run of a week doesn't count for the others.
+	assert.Equal(t, 5.0/6.0, weekly.CompletionRate(runs, today.AddDate(0, 0, -16), today))
+}
@@ -1,27 +1,55 @@
 package model
 
 import (
+	"time"
+
 	"github.com/apoloa/bjournal/src/utils"
+	"github.com/google/uuid"
 )
 
+// LogLink points to a log of another day.
+type LogLink struct {
+	// Date is the key of the day, DD.MM.YYYY.
+	Date string `json:"date" yaml:"date"`
+	Id   string `json:"id" yaml:"id"`
+}
+
 type Log struct {
 	Parent    *Log     `json:"-" yaml:"-"`
-	Id        string   `json:"-" yaml:"-"`
+	Id        string   `json:"id" yaml:"id"`
 	Name      string   `json:"name" yaml:"name"`
 	Mark      Category `json:"mark" yaml:"mark"`
 	Important bool     `json:"important" yaml:"important"`
 	Url       *string  `json:"url,omitempty" yaml:"url,omitempty"`
 	Text      *string  `json:"-" yaml:"-"`
 	SubLogs   *[]Log   `json:"sub_logs,omitempty" yaml:"subLogs,omitempty"`
+	// MigratedFrom is the log this one is a migrated copy of.
+	MigratedFrom *LogLink `json:"migrated_from,omitempty" yaml:"migratedFrom,omitempty"`
+	// MigratedTo is the copy of this log in the day it was migrated to.
+	MigratedTo *LogLink `json:"migrated_to,omitempty" yaml:"migratedTo,omitempty"`
+	// Migrations is the number of times the log was pushed forward.
+	Migrations int `json:"migrations,omitempty" yaml:"migrations,omitempty"`
+	// Due is when the task must be done.
+	Due *Moment `json:"due,omitempty" yaml:"due,omitempty"`
+	// At is when the event or the note happens.
+	At *Moment `json:"at,omitempty" yaml:"at,omitempty"`
+	// Tags and Contexts are the #tags and @contexts of the name, see
+	// ParseTags. They are not stored in the day files.
+	Tags     []string `json:"tags,omitempty" yaml:"-"`
+	Contexts []string `json:"contexts,omitempty" yaml:"-"`
 }
 
 func NewLog(name string, category Category) Log {
+	tags, contexts := ParseTags(name)
 	return Log{
+		Id:        uuid.NewString(),
 		Name:      name,
 		Mark:      category,
 		Important: false,
 		Url:       nil,
 		Text:      nil,
+		Tags:      tags,
+		Contexts:  contexts,
 	}
 }
 
@@ -33,10 +61,200 @@ func (l *Log) GetName() string {
 }
 
 func (l *Log) AppendNewSubLog(name string, category Category) {
+	l.AppendSubLog(NewLog(name, category))
+}
+
+func (l *Log) AppendSubLog(log Log) {
 	if l.SubLogs == nil {
 		l.SubLogs = &[]Log{}
 	}
-	*l.SubLogs = append(*l.SubLogs, NewLog(name, category))
+	*l.SubLogs = append(*l.SubLogs, log)
+}
+
+// Moment returns when the task is due or the event happens, nil if it has no
+// moment.
+func (l *Log) Moment() *Moment {
+	if l.Due != nil {
+		return l.Due
+	}
+	return l.At
+}
+
+// SetMoment sets when the task is due, or when the other logs happen.
+func (l *Log) SetMoment(moment *Moment) {
+	l.Due, l.At = nil, nil
+	if l.Mark == Event || l.Mark == Note {
+		l.At = moment
+	} else {
+		l.Due = moment
+	}
+}
+
+// IsOverdue checks if the task is still open after it was due.
+func (l *Log) IsOverdue(now time.Time) bool {
+	return l.IsATask() && l.Due != nil && now.After(l.Due.End())
+}
+
+// CountSubLogs returns the number of sub logs at any depth.
+func (l *Log) CountSubLogs() int {
+	if l.SubLogs == nil {
+		return 0
+	}
+	count := len(*l.SubLogs)
+	for i := range *l.SubLogs {
+		count += (*l.SubLogs)[i].CountSubLogs()
+	}
+	return count
+}
+
+// Clone returns a deep copy of the log and its sub logs, keeping the ids.
+func (l Log) Clone() Log {
+	l.Parent = nil
+	l.Url = cloneString(l.Url)
+	l.Text = cloneString(l.Text)
+	l.MigratedFrom = cloneLink(l.MigratedFrom)
+	l.MigratedTo = cloneLink(l.MigratedTo)
+	l.Due = cloneMoment(l.Due)
+	l.At = cloneMoment(l.At)
+	l.Tags = cloneStrings(l.Tags)
+	l.Contexts = cloneStrings(l.Contexts)
+	if l.SubLogs != nil {
+		subLogs := make([]Log, len(*l.SubLogs))
+		for i, subLog := range *l.SubLogs {
+			subLogs[i] = subLog.Clone()
+		}
+		l.SubLogs = &subLogs
+	}
+	return l
+}
+
+func cloneString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func cloneLink(link *LogLink) *LogLink {
+	if link == nil {
+		return nil
+	}
+	copied := *link
+	return &copied
+}
+
+func cloneMoment(moment *Moment) *Moment {
+	if moment == nil {
+		return nil
+	}
+	copied := *moment
+	return &copied
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
+
+// MigrationCopy returns a copy of the log and its sub logs with new ids, that
+// link to the logs they were copied from in the day with the key.
+func (l Log) MigrationCopy(from string) Log {
+	copied := l.Clone()
+	copied.linkMigration(from)
+	return copied
+}
+
+func (l *Log) linkMigration(from string) {
+	l.MigratedFrom = &LogLink{Date: from, Id: l.Id}
+	l.MigratedTo = nil
+	l.Migrations++
+	l.Id = uuid.NewString()
+	if l.SubLogs != nil {
+		for i := range *l.SubLogs {
+			(*l.SubLogs)[i].linkMigration(from)
+		}
+	}
+}
+
+// RenewIds assigns new ids to the log and its sub logs.
+func (l *Log) RenewIds() {
+	l.Id = uuid.NewString()
+	if l.SubLogs != nil {
+		for i := range *l.SubLogs {
+			(*l.SubLogs)[i].RenewIds()
+		}
+	}
+}
+
+// fillIds assigns an id to the logs without it, returning if any was missing.
+func (l *Log) fillIds() bool {
+	filled := false
+	if l.Id == "" {
+		l.Id = uuid.NewString()
+		filled = true
+	}
+	if l.SubLogs != nil {
+		for i := range *l.SubLogs {
+			if (*l.SubLogs)[i].fillIds() {
+				filled = true
+			}
+		}
+	}
+	return filled
+}
+
+// removeLog removes the log with the given id from the logs or their sub logs.
+func removeLog(logs *[]Log, id string) bool {
+	for i := range *logs {
+		if (*logs)[i].Id == id {
+			*logs = append((*logs)[:i], (*logs)[i+1:]...)
+			return true
+		}
+		if (*logs)[i].SubLogs != nil && removeLog((*logs)[i].SubLogs, id) {
+			if len(*(*logs)[i].SubLogs) == 0 {
+				(*logs)[i].SubLogs = nil
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the log or sub log with the given id.
+func (l *Log) Find(id string) *Log {
+	if l.Id == id {
+		return l
+	}
+	if l.SubLogs != nil {
+		for i := range *l.SubLogs {
+			if found := (*l.SubLogs)[i].Find(id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// MarkAs applies one of the task marks, returning false if the log is not a
+// task or the mark can't be applied.
+func (l *Log) MarkAs(category Category) bool {
+	if !l.IsATask() {
+		return false
+	}
+	switch category {
+	case Complete:
+		l.MarkAsComplete()
+	case Irrelevant:
+		l.MarkAsIrrelevant()
+	case Migrated:
+		l.MarkAsMigrated()
+	default:
+		return false
+	}
+	return true
 }
 
 func (l *Log) MarkAsComplete() {
@@ -51,17 +269,25 @@ func (l *Log) MarkAsIrrelevant() {
 	}
 }
 
+// MarkAsMigrated marks the task and every task below it as migrated.
 func (l *Log) MarkAsMigrated() {
 	if l.Mark == Task {
 		l.Mark = Migrated
-		if l.SubLogs != nil {
-			for i, _ := range *l.SubLogs {
-				(*l.SubLogs)[i].MarkAsMigrated()
-			}
+	}
+	if l.SubLogs != nil {
+		for i := range *l.SubLogs {
+			(*l.SubLogs)[i].MarkAsMigrated()
 		}
 	}
 }
 
+// MarkAsScheduled marks the task as moved to the future log.
+func (l *Log) MarkAsScheduled() {
+	if l.Mark == Task {
+		l.Mark = Scheduled
+	}
+}
+
 func (l *Log) IsATask() bool {
 	return l.Mark == Task
 }
@@ -77,3 +303,7 @@ func (l *Log) IsMigrated() bool {
 func (l *Log) IsIrrelevant() bool {
 	return l.Mark == Irrelevant
 }
+
+func (l *Log) IsScheduled() bool {
+	return l.Mark == Scheduled
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	momentDateLayout = "2006-01-02"
+	momentLayout     = "2006-01-02 15:04"
+	clockLayout      = "15:04"
+	// MomentPrefix starts the moment in the text of a new entry, like
+	// ^2026-10-20 14:00.
+	MomentPrefix = '^'
+)
+
+// Moment is a date with an optional time of the day, stored as YYYY-MM-DD or
+// YYYY-MM-DD HH:MM in the local time.
+type Moment struct {
+	Time time.Time
+	// AllDay is true when the moment has no time.
+	AllDay bool
+}
+
+// ParseMoment parses YYYY-MM-DD or YYYY-MM-DD HH:MM.
+func ParseMoment(value string) (Moment, error) {
+	value = strings.Join(strings.Fields(value), " ")
+	if date, err := time.ParseInLocation(momentDateLayout, value, time.Local); err == nil {
+		return Moment{Time: date, AllDay: true}, nil
+	}
+	date, err := time.ParseInLocation(momentLayout, value, time.Local)
+	if err != nil {
+		return Moment{}, fmt.Errorf("invalid moment %q, expected YYYY-MM-DD or YYYY-MM-DD HH:MM", value)
+	}
+	return Moment{Time: date}, nil
+}
+
+func (m Moment) String() string {
+	if m.AllDay {
+		return m.Time.Format(momentDateLayout)
+	}
+	return m.Time.Format(momentLayout)
+}
+
+// Clock returns the time of the day as HH:MM, empty for the all day moments.
+func (m Moment) Clock() string {
+	if m.AllDay {
+		return ""
+	}
+	return m.Time.Format(clockLayout)
+}
+
+// End returns when the moment is over, the end of the day for the all day
+// moments.
+func (m Moment) End() time.Time {
+	if m.AllDay {
+		return m.Time.AddDate(0, 0, 1)
+	}
+	return m.Time
+}
+
+// SameDay checks if the moment is in the day of the date.
+func (m Moment) SameDay(date time.Time) bool {
+	return m.Time.Year() == date.Year() && m.Time.YearDay() == date.YearDay()
+}
+
+func (m Moment) MarshalYAML() (interface{}, error) {
+	return m.String(), nil
+}
+
+func (m *Moment) UnmarshalYAML(value *yaml.Node) error {
+	moment, err := ParseMoment(value.Value)
+	if err != nil {
+		return err
+	}
+	*m = moment
+	return nil
+}
+
+func (m Moment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+func (m *Moment) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	moment, err := ParseMoment(value)
+	if err != nil {
+		return err
+	}
+	*m = moment
+	return nil
+}
+
+// ParseMomentText splits the text of an entry in the name and the moment
+// written after a ^ at the start of a word. The moment is a date accepted by
+// timeconv.ParseDate relative to the day of the entry, optionally followed by
+// HH:MM, or a time alone on the day of the entry:
+//
+//	Dentist ^2026-10-20 14:00
+//	Send the report ^friday
+//	Standup ^9:30
+//
+// The words after the moment stay in the name. The name is the whole text
+// when there is no moment.
+func ParseMomentText(text string, day time.Time) (string, *Moment, error) {
+	index := strings.LastIndexByte(text, MomentPrefix)
+	if index < 0 || (index > 0 && !unicode.IsSpace(rune(text[index-1]))) {
+		return text, nil, nil
+	}
+	fields := strings.Fields(text[index+1:])
+	if len(fields) == 0 {
+		return text, nil, errors.New("missing the date after ^")
+	}
+	moment, used, err := parseMomentFields(fields, day)
+	if err != nil {
+		return text, nil, err
+	}
+	name := strings.TrimSpace(strings.Join(append(strings.Fields(text[:index]), fields[used:]...), " "))
+	return name, &moment, nil
+}
+
+// parseMomentFields parses the moment at the start of the fields and returns
+// the number of fields it used.
+func parseMomentFields(fields []string, day time.Time) (Moment, int, error) {
+	if clock, err := time.Parse(clockLayout, fields[0]); err == nil {
+		return Moment{Time: time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)}, 1, nil
+	}
+	// The longest date first, like "next friday" or "3 days ago".
+	for used := 3; used > 0; used-- {
+		if used > len(fields) {
+			continue
+		}
+		date, err := timeconv.ParseDate(strings.Join(fields[:used], " "), day)
+		if err != nil {
+			continue
+		}
+		if used < len(fields) {
+			if clock, err := time.Parse(clockLayout, fields[used]); err == nil {
+				return Moment{Time: time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)}, used + 1, nil
+			}
+		}
+		return Moment{Time: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local), AllDay: true}, used, nil
+	}
+	return Moment{}, 0, fmt.Errorf("invalid date after ^ %q, expected YYYY-MM-DD HH:MM, friday, tomorrow...", strings.Join(fields, " "))
+}
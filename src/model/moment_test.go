@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseMomentText(t *testing.T) {
+	// A saturday.
+	day := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		text   string
+		name   string
+		moment string
+	}{
+		{"Dentist ^2026-10-20 14:00", "Dentist", "2026-10-20 14:00"},
+		{"Send the report ^next friday #work", "Send the report #work", "2026-10-23"},
+		{"Standup ^9:30", "Standup", "2026-10-17 09:30"},
+		{"Call ^tomorrow 8:05 about the bike", "Call about the bike", "2026-10-18 08:05"},
+	}
+	for _, test := range tests {
+		name, moment, err := ParseMomentText(test.text, day)
+		assert.Nil(t, err, test.text)
+		assert.Equal(t, test.name, name, test.text)
+		assert.Equal(t, test.moment, moment.String(), test.text)
+	}
+
+	name, moment, err := ParseMomentText("2^10 is 1024", day)
+	assert.Nil(t, err)
+	assert.Nil(t, moment)
+	assert.Equal(t, "2^10 is 1024", name)
+	_, _, err = ParseMomentText("Dentist ^someday", day)
+	assert.NotNil(t, err)
+}
+
+func TestMomentRoundTrip(t *testing.T) {
+	log := NewLog("Dentist", Event)
+	moment, err := ParseMoment("2026-10-20 14:00")
+	assert.Nil(t, err)
+	log.SetMoment(&moment)
+	assert.Nil(t, log.Due)
+	data, err := yaml.Marshal(log)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "at: 2026-10-20 14:00\n")
+	var read Log
+	assert.Nil(t, yaml.Unmarshal(data, &read))
+	assert.Equal(t, moment, *read.At)
+
+	task := NewLog("Report", Task)
+	due, _ := ParseMoment("2026-10-20")
+	task.SetMoment(&due)
+	assert.False(t, task.IsOverdue(time.Date(2026, time.October, 20, 23, 0, 0, 0, time.Local)))
+	assert.True(t, task.IsOverdue(time.Date(2026, time.October, 21, 0, 1, 0, 0, time.Local)))
+	task.MarkAsComplete()
+	assert.False(t, task.IsOverdue(time.Date(2026, time.October, 21, 0, 1, 0, 0, time.Local)))
+}
@@ -1,12 +1,136 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
 
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"gopkg.in/yaml.v3"
+)
+
+// MonthlyLog is the monthly spread: a calendar with the events of each day and
+// the tasks of the month.
 type MonthlyLog struct {
-	Days map[string][]Log `yaml:"days"`
-	Logs []Log            `yaml:"items"`
+	key  string           `yaml:"-"`
+	Date time.Time        `json:"-" yaml:"-"`
+	Days map[string][]Log `json:"days" yaml:"days"`
+	Logs []Log            `json:"logs" yaml:"items"`
 }
 
 func NewMonthlyLog(date time.Time) MonthlyLog {
-	return MonthlyLog{}
+	return MonthlyLog{
+		key:  timeconv.TimeToMonthString(date),
+		Date: time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()),
+		Days: map[string][]Log{},
+		Logs: []Log{},
+	}
+}
+
+func MonthlyFrom(from []byte, date time.Time) (MonthlyLog, error) {
+	monthlyLog := NewMonthlyLog(date)
+	err := yaml.Unmarshal(from, &monthlyLog)
+	if err != nil {
+		return monthlyLog, err
+	}
+	if monthlyLog.Days == nil {
+		monthlyLog.Days = map[string][]Log{}
+	}
+	if monthlyLog.Logs == nil {
+		monthlyLog.Logs = []Log{}
+	}
+	for day := range monthlyLog.Days {
+		for i := range monthlyLog.Days[day] {
+			monthlyLog.Days[day][i].fillIds()
+		}
+	}
+	for i := range monthlyLog.Logs {
+		monthlyLog.Logs[i].fillIds()
+	}
+	return monthlyLog, nil
+}
+
+// Key returns the name of the month file without extension.
+func (m *MonthlyLog) Key() string {
+	return m.key
+}
+
+// DaysInMonth returns the number of days of the month.
+func (m *MonthlyLog) DaysInMonth() int {
+	return m.Date.AddDate(0, 1, -1).Day()
+}
+
+// DayDate returns the date of the day of the month.
+func (m *MonthlyLog) DayDate(day int) time.Time {
+	return m.Date.AddDate(0, 0, day-1)
+}
+
+func dayKey(day int) string {
+	return fmt.Sprintf("%02d", day)
+}
+
+// DayLogs returns the logs of the calendar for the day of the month.
+func (m *MonthlyLog) DayLogs(day int) []Log {
+	return m.Days[dayKey(day)]
+}
+
+// AddDayLog adds the log to the calendar in the day of the month.
+func (m *MonthlyLog) AddDayLog(day int, log Log) {
+	m.Days[dayKey(day)] = append(m.Days[dayKey(day)], log)
+}
+
+// Find returns the log of the calendar or the tasks with the given id.
+func (m *MonthlyLog) Find(id string) *Log {
+	for i := range m.Logs {
+		if found := m.Logs[i].Find(id); found != nil {
+			return found
+		}
+	}
+	for day := range m.Days {
+		for i := range m.Days[day] {
+			if found := m.Days[day][i].Find(id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// Remove deletes the log of the calendar or the tasks with the given id.
+func (m *MonthlyLog) Remove(id string) bool {
+	if removeLog(&m.Logs, id) {
+		return true
+	}
+	for day, logs := range m.Days {
+		if removeLog(&logs, id) {
+			m.Days[day] = logs
+			if len(logs) == 0 {
+				delete(m.Days, day)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Copy returns a deep copy of the month.
+func (m MonthlyLog) Copy() MonthlyLog {
+	logs := make([]Log, len(m.Logs))
+	for i, log := range m.Logs {
+		logs[i] = log.Clone()
+	}
+	days := make(map[string][]Log, len(m.Days))
+	for day, dayLogs := range m.Days {
+		copied := make([]Log, len(dayLogs))
+		for i, log := range dayLogs {
+			copied[i] = log.Clone()
+		}
+		days[day] = copied
+	}
+	m.Logs = logs
+	m.Days = days
+	return m
+}
+
+func (m *MonthlyLog) ToBytes() ([]byte, error) {
+	return yaml.Marshal(m)
 }
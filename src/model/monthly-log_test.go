@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewMonthlyLog(t *testing.T) {
@@ -21,4 +23,35 @@ func TestNewMonthlyLog(t *testing.T) {
 
 	fmt.Println(firstOfMonth)
 	fmt.Println(lastOfMonth)
+
+	monthlyLog := NewMonthlyLog(now)
+	assert.Equal(t, firstOfMonth, monthlyLog.Date)
+	assert.Equal(t, lastOfMonth.Day(), monthlyLog.DaysInMonth())
+	assert.Equal(t, lastOfMonth, monthlyLog.DayDate(monthlyLog.DaysInMonth()))
+}
+
+func TestMonthlyLogRoundTrip(t *testing.T) {
+	date := time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC)
+	monthlyLog := NewMonthlyLog(date)
+	assert.Equal(t, "02.2026", monthlyLog.Key())
+	assert.Equal(t, 28, monthlyLog.DaysInMonth())
+
+	event := NewLog("Dentist", Event)
+	monthlyLog.AddDayLog(3, event)
+	monthlyLog.Logs = append(monthlyLog.Logs, NewLog("Pay taxes", Task))
+
+	bytes, err := monthlyLog.ToBytes()
+	assert.Nil(t, err)
+	read, err := MonthlyFrom(bytes, date)
+	assert.Nil(t, err)
+	assert.Equal(t, "Dentist", read.DayLogs(3)[0].Name)
+	assert.Equal(t, event.Id, read.Find(event.Id).Id)
+	assert.Equal(t, "Pay taxes", read.Logs[0].Name)
+
+	copied := read.Copy()
+	copied.Find(event.Id).Name = "changed"
+	assert.Equal(t, "Dentist", read.DayLogs(3)[0].Name)
+
+	assert.True(t, read.Remove(event.Id))
+	assert.Empty(t, read.DayLogs(3))
 }
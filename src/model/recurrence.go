@@ -0,0 +1,365 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Frequency is the period a recurrence rule repeats on.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// rruleDays are the weekdays of the RRULE syntax.
+var rruleDays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule tells the days a recurrence happens on.
+type Rule struct {
+	Freq Frequency
+	// Interval repeats the rule every so many periods, 1 is every period.
+	Interval int
+	// Weekdays and MonthDays restrict the days of the period, a negative
+	// month day counts from the end of the month. Without them a weekly rule
+	// repeats on the weekday of the start and a monthly one on its day.
+	Weekdays  []time.Weekday
+	MonthDays []int
+	// Until is the last day of the rule, zero when it doesn't end.
+	Until time.Time
+}
+
+// ParseRule parses a rule written as daily, weekdays, "weekly on monday,
+// friday", "monthly on day 15" (or "on last" for the last day), or as the
+// subset of RRULE with FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL, BYDAY
+// without positions, BYMONTHDAY and UNTIL, like FREQ=WEEKLY;BYDAY=MO,WE.
+func ParseRule(text string) (Rule, error) {
+	text = strings.TrimSpace(text)
+	upper := strings.ToUpper(text)
+	if strings.HasPrefix(upper, "RRULE:") || strings.HasPrefix(upper, "FREQ=") {
+		return parseRRule(strings.TrimPrefix(upper, "RRULE:"))
+	}
+	fields := strings.Fields(strings.ToLower(strings.ReplaceAll(text, ",", " ")))
+	if len(fields) == 0 {
+		return Rule{}, errors.New("the rule is empty")
+	}
+	rule := Rule{Interval: 1}
+	switch fields[0] {
+	case "daily":
+		rule.Freq = Daily
+	case "weekdays":
+		rule.Freq = Weekly
+		rule.Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	case "weekly":
+		rule.Freq = Weekly
+		for _, field := range onFields(fields) {
+			if field == "and" {
+				continue
+			}
+			weekday, ok := timeconv.ParseWeekday(field)
+			if !ok {
+				return rule, fmt.Errorf("invalid weekday %q in rule %q", field, text)
+			}
+			rule.Weekdays = append(rule.Weekdays, weekday)
+		}
+	case "monthly":
+		rule.Freq = Monthly
+		for _, field := range onFields(fields) {
+			if field == "day" || field == "and" {
+				continue
+			}
+			day, err := parseMonthDay(field)
+			if err != nil {
+				return rule, fmt.Errorf("%w in rule %q", err, text)
+			}
+			rule.MonthDays = append(rule.MonthDays, day)
+		}
+	default:
+		return rule, fmt.Errorf("invalid rule %q, expected daily, weekdays, weekly, monthly or an RRULE", text)
+	}
+	if (rule.Freq == Daily || fields[0] == "weekdays") && len(fields) > 1 {
+		return rule, fmt.Errorf("invalid rule %q", text)
+	}
+	return rule, nil
+}
+
+// onFields returns the fields after "on", the fields of "weekly on monday".
+func onFields(fields []string) []string {
+	if len(fields) > 1 && fields[1] == "on" {
+		return fields[2:]
+	}
+	return fields[1:]
+}
+
+func parseMonthDay(field string) (int, error) {
+	if field == "last" {
+		return -1, nil
+	}
+	day, err := strconv.Atoi(strings.TrimRight(field, "stndrh"))
+	if err != nil || day == 0 || day < -31 || day > 31 {
+		return 0, fmt.Errorf("invalid day of the month %q", field)
+	}
+	return day, nil
+}
+
+func parseRRule(text string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	for _, part := range strings.Split(text, ";") {
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		switch name {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly:
+				rule.Freq = Frequency(value)
+			default:
+				return rule, fmt.Errorf("unsupported RRULE frequency %q", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return rule, fmt.Errorf("invalid RRULE interval %q", value)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := rruleDays[day]
+				if !ok {
+					return rule, fmt.Errorf("unsupported RRULE day %q", day)
+				}
+				rule.Weekdays = append(rule.Weekdays, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := parseMonthDay(day)
+				if err != nil {
+					return rule, err
+				}
+				rule.MonthDays = append(rule.MonthDays, monthDay)
+			}
+		case "UNTIL":
+			// Only the day of the limit is taken into account.
+			if len(value) < 8 {
+				return rule, fmt.Errorf("invalid RRULE until %q", value)
+			}
+			until, err := time.ParseInLocation("20060102", value[:8], time.Local)
+			if err != nil {
+				return rule, fmt.Errorf("invalid RRULE until %q", value)
+			}
+			rule.Until = until
+		default:
+			return rule, fmt.Errorf("unsupported RRULE part %q", name)
+		}
+	}
+	if rule.Freq == "" {
+		return rule, errors.New("the RRULE has no FREQ")
+	}
+	return rule, nil
+}
+
+// Matches checks if the rule that started on the day of start happens on the
+// day of date.
+func (r Rule) Matches(start, date time.Time) bool {
+	// The days are compared in UTC so the daylight saving changes don't count.
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(start) {
+		return false
+	}
+	if !r.Until.IsZero() && day.After(time.Date(r.Until.Year(), r.Until.Month(), r.Until.Day(), 0, 0, 0, 0, time.UTC)) {
+		return false
+	}
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	if len(r.Weekdays) > 0 && !containsWeekday(r.Weekdays, day.Weekday()) {
+		return false
+	}
+	if len(r.MonthDays) > 0 && !matchesMonthDay(r.MonthDays, day) {
+		return false
+	}
+	switch r.Freq {
+	case Daily:
+		return daysBetween(start, day)%interval == 0
+	case Weekly:
+		if len(r.Weekdays) == 0 && day.Weekday() != start.Weekday() {
+			return false
+		}
+		return daysBetween(weekStart(start), weekStart(day))/7%interval == 0
+	case Monthly:
+		if len(r.Weekdays) == 0 && len(r.MonthDays) == 0 && day.Day() != start.Day() {
+			return false
+		}
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		return months%interval == 0
+	}
+	return false
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// weekStart returns the monday of the week of the day.
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMonthDay(monthDays []int, day time.Time) bool {
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, monthDay := range monthDays {
+		if monthDay == day.Day() || (monthDay < 0 && last+monthDay+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Recurrence is an entry added to every day of its rule.
+type Recurrence struct {
+	Id   string `json:"id" yaml:"id"`
+	Rule string `json:"rule" yaml:"rule"`
+	// Start is the first day of the rule, YYYY-MM-DD.
+	Start     string   `json:"start" yaml:"start"`
+	Name      string   `json:"name" yaml:"name"`
+	Mark      Category `json:"mark" yaml:"mark"`
+	Important bool     `json:"important,omitempty" yaml:"important,omitempty"`
+	// Time is when the entries happen or are due, HH:MM, empty for all the
+	// day.
+	Time string `json:"time,omitempty" yaml:"time,omitempty"`
+}
+
+// NewRecurrence returns a recurrence of the entry starting on the day of
+// start.
+func NewRecurrence(rule, name string, mark Category, start time.Time) Recurrence {
+	return Recurrence{
+		Id:    uuid.NewString(),
+		Rule:  rule,
+		Start: start.Format(momentDateLayout),
+		Name:  name,
+		Mark:  mark,
+	}
+}
+
+// Validate checks the rule, the start, the mark and the time.
+func (r Recurrence) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("the name is empty")
+	}
+	switch r.Mark {
+	case Task, Note, Event:
+	default:
+		return fmt.Errorf("invalid mark %q, expected task, note or event", r.Mark)
+	}
+	if _, err := ParseRule(r.Rule); err != nil {
+		return err
+	}
+	if _, err := time.ParseInLocation(momentDateLayout, r.Start, time.Local); err != nil {
+		return fmt.Errorf("invalid start %q, expected YYYY-MM-DD", r.Start)
+	}
+	if r.Time != "" {
+		if _, err := time.Parse(clockLayout, r.Time); err != nil {
+			return fmt.Errorf("invalid time %q, expected HH:MM", r.Time)
+		}
+	}
+	return nil
+}
+
+// Occurs checks if the recurrence happens on the day of date.
+func (r Recurrence) Occurs(date time.Time) (bool, error) {
+	rule, err := ParseRule(r.Rule)
+	if err != nil {
+		return false, err
+	}
+	start, err := time.ParseInLocation(momentDateLayout, r.Start, time.Local)
+	if err != nil {
+		return false, fmt.Errorf("invalid start %q, expected YYYY-MM-DD", r.Start)
+	}
+	return rule.Matches(start, date), nil
+}
+
+// Instance returns a new entry of the recurrence for the day of date.
+func (r Recurrence) Instance(date time.Time) Log {
+	log := NewLog(r.Name, r.Mark)
+	log.Important = r.Important
+	if clock, err := time.Parse(clockLayout, r.Time); err == nil {
+		log.SetMoment(&Moment{Time: time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)})
+	}
+	return log
+}
+
+// RecurrenceLog contains the recurrences of the journal.
+type RecurrenceLog struct {
+	idsFilled bool         `yaml:"-"`
+	Entries   []Recurrence `json:"entries" yaml:"items"`
+}
+
+func RecurrencesFrom(from []byte) (RecurrenceLog, error) {
+	recurrenceLog := RecurrenceLog{}
+	if err := yaml.Unmarshal(from, &recurrenceLog); err != nil {
+		return recurrenceLog, err
+	}
+	if recurrenceLog.Entries == nil {
+		recurrenceLog.Entries = []Recurrence{}
+	}
+	for i := range recurrenceLog.Entries {
+		if recurrenceLog.Entries[i].Id == "" {
+			recurrenceLog.Entries[i].Id = uuid.NewString()
+			recurrenceLog.idsFilled = true
+		}
+	}
+	return recurrenceLog, nil
+}
+
+// IdsFilled checks if any recurrence written by hand got a new id when it was
+// read, so the file must be saved to keep the ids.
+func (r *RecurrenceLog) IdsFilled() bool {
+	return r.idsFilled
+}
+
+// Remove deletes the recurrence with the given id.
+func (r *RecurrenceLog) Remove(id string) bool {
+	for i := range r.Entries {
+		if r.Entries[i].Id == id {
+			r.Entries = append(r.Entries[:i], r.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RecurrenceLog) ToBytes() ([]byte, error) {
+	return yaml.Marshal(r)
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleMatches(t *testing.T) {
+	// Thursday.
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local)
+	day := func(d int) time.Time {
+		return time.Date(2026, time.October, d, 0, 0, 0, 0, time.Local)
+	}
+	cases := []struct {
+		rule string
+		days []int
+	}{
+		{"daily", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}},
+		{"weekdays", []int{1, 2, 5, 6, 7, 8, 9, 12, 13, 14, 15}},
+		{"weekly", []int{1, 8, 15}},
+		{"weekly on monday, fri", []int{2, 5, 9, 12}},
+		{"monthly on day 3", []int{3}},
+		{"FREQ=DAILY;INTERVAL=3", []int{1, 4, 7, 10, 13}},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", []int{1, 12, 15}},
+		{"FREQ=DAILY;UNTIL=20261003T000000Z", []int{1, 2, 3}},
+	}
+	for _, c := range cases {
+		rule, err := ParseRule(c.rule)
+		assert.Nil(t, err, c.rule)
+		var days []int
+		for d := 1; d <= 15; d++ {
+			if rule.Matches(start, day(d)) {
+				days = append(days, d)
+			}
+		}
+		assert.Equal(t, c.days, days, c.rule)
+	}
+
+	last, err := ParseRule("monthly on last")
+	assert.Nil(t, err)
+	assert.True(t, last.Matches(start, time.Date(2027, time.February, 28, 0, 0, 0, 0, time.Local)))
+	assert.False(t, last.Matches(start, time.Date(2027, time.February, 27, 0, 0, 0, 0, time.Local)))
+	assert.False(t, last.Matches(start, time.Date(2026, time.September, 30, 0, 0, 0, 0, time.Local)))
+
+	for _, invalid := range []string{"", "hourly", "daily on monday", "weekly on someday", "monthly on 32", "FREQ=YEARLY", "FREQ=MONTHLY;BYDAY=1MO"} {
+		_, err := ParseRule(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestRecurrenceInstance(t *testing.T) {
+	date := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local)
+	standup := NewRecurrence("weekdays", "Standup #work", Event, date)
+	standup.Time = "09:30"
+	assert.Nil(t, standup.Validate())
+	log := standup.Instance(date)
+	assert.Equal(t, "Standup #work", log.Name)
+	assert.Equal(t, []string{"work"}, log.Tags)
+	assert.Equal(t, "2026-10-19 09:30", log.At.String())
+
+	standup.Time = "9.30"
+	assert.NotNil(t, standup.Validate())
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// TagPrefix starts the tags of a text, like #work.
+	TagPrefix = '#'
+	// ContextPrefix starts the contexts of a text, like @home.
+	ContextPrefix = '@'
+)
+
+// TagCount is a tag or a context, with its prefix, and the number of logs
+// that have it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// ParseTags returns the #tags and @contexts of the text, in lower case,
+// without their prefixes and without repetitions.
+func ParseTags(text string) (tags []string, contexts []string) {
+	for _, word := range strings.FieldsFunc(text, IsTagSeparator) {
+		runes := []rune(word)
+		if len(runes) < 2 || !isTagName(runes[1:]) {
+			continue
+		}
+		name := strings.ToLower(string(runes[1:]))
+		switch runes[0] {
+		case TagPrefix:
+			tags = appendTag(tags, name)
+		case ContextPrefix:
+			contexts = appendTag(contexts, name)
+		}
+	}
+	return tags, contexts
+}
+
+// IsTagSeparator checks if the rune ends a word that can be a tag.
+func IsTagSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == ',' || r == '.' || r == ';' || r == ':' || r == '(' || r == ')' || r == '!' || r == '?'
+}
+
+func isTagName(runes []rune) bool {
+	for _, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+func appendTag(tags []string, tag string) []string {
+	for _, existing := range tags {
+		if existing == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+// HasTag checks if the log has the tag, without its prefix.
+func (l *Log) HasTag(tag string) bool {
+	return containsTag(l.Tags, tag)
+}
+
+// HasContext checks if the log has the context, without its prefix.
+func (l *Log) HasContext(context string) bool {
+	return containsTag(l.Contexts, context)
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTags fills the tags and contexts of the log and its sub logs from
+// their names.
+func (l *Log) parseTags() {
+	l.Tags, l.Contexts = ParseTags(l.Name)
+	if l.SubLogs != nil {
+		for i := range *l.SubLogs {
+			(*l.SubLogs)[i].parseTags()
+		}
+	}
+}
+
+// ParseTags fills the tags and contexts of every log of the day from their
+// names.
+func (d *DailyLog) ParseTags() {
+	for i := range d.Logs {
+		d.Logs[i].parseTags()
+	}
+}
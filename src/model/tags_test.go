@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, contexts := ParseTags("Call the bank #Work, #work/bank (@home) about #2026 @ me@mail.com #bad!")
+	assert.Equal(t, []string{"work", "work/bank", "2026", "bad"}, tags)
+	assert.Equal(t, []string{"home"}, contexts)
+
+	log := NewLog("Buy a tube #bike", Task)
+	assert.True(t, log.HasTag("bike"))
+	log.AppendNewSubLog("At the shop @town", Note)
+	dailyLog := DailyLog{Logs: []Log{log}}
+	dailyLog.Logs[0].Name = "Buy a tube"
+	dailyLog.ParseTags()
+	assert.Empty(t, dailyLog.Logs[0].Tags)
+	assert.True(t, (*dailyLog.Logs[0].SubLogs)[0].HasContext("town"))
+}
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/apoloa/bjournal/src/config"
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/apoloa/bjournal/src/service"
+	zerolog "github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultInterval is the time between two checks of the journal.
+	DefaultInterval = 15 * time.Second
+
+	// hookTimeout is the time a hook can run before it is stopped.
+	hookTimeout = 30 * time.Second
+)
+
+// Runner runs the programs of the hooks, replaced in the tests.
+type Runner interface {
+	Run(ctx context.Context, env []string, name string, args ...string) error
+}
+
+// execRunner runs the programs as child processes.
+type execRunner struct{}
+
+func (execRunner) Run(ctx context.Context, env []string, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %w: %s", name, err, output)
+	}
+	return nil
+}
+
+// Scheduler fires the hooks when the events of the journal start and when
+// its tasks go overdue. Only the moments passed while it runs fire hooks, the
+// ones missed while it was stopped are not caught up.
+type Scheduler struct {
+	logService *service.LogService
+	hooks      []config.Hook
+	// now returns the current time and runner runs the hooks, replaced in the
+	// tests.
+	now    func() time.Time
+	runner Runner
+	// last is the time of the previous check.
+	last time.Time
+}
+
+// NewScheduler returns a scheduler of the hooks for the journal.
+func NewScheduler(logService *service.LogService, hooks []config.Hook) *Scheduler {
+	return &Scheduler{
+		logService: logService,
+		hooks:      hooks,
+		now:        time.Now,
+		runner:     execRunner{},
+	}
+}
+
+// Run checks the journal every interval until the context is done.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	s.last = s.now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.check(ctx)
+		}
+	}
+}
+
+// check fires the hooks of the moments passed since the previous check.
+func (s *Scheduler) check(ctx context.Context) {
+	now := s.now()
+	from := s.last
+	s.last = now
+	for _, hook := range s.hooks {
+		results, err := s.due(hook, from, now)
+		if err != nil {
+			zerolog.Print("Error reading the journal for the hooks ", err)
+			return
+		}
+		for _, result := range results {
+			if err := s.fire(ctx, hook, result); err != nil {
+				zerolog.Print("Error running the hook ", err)
+			}
+		}
+	}
+}
+
+// due returns the entries whose hook time is after from and not after now.
+func (s *Scheduler) due(hook config.Hook, from, now time.Time) ([]search.Result, error) {
+	var results []search.Result
+	switch hook.On {
+	case config.HookStart:
+		// The range of the agenda is by days, the times are checked below.
+		agenda, err := s.logService.Agenda(from.Add(hook.Before), now.Add(hook.Before))
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range agenda {
+			fireTime := result.Log.Moment().Time.Add(-hook.Before)
+			if result.Log.Mark == model.Event && fireTime.After(from) && !fireTime.After(now) {
+				results = append(results, result)
+			}
+		}
+	case config.HookOverdue:
+		overdue, err := s.logService.Overdue(now)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range overdue {
+			if result.Log.Due.End().After(from) {
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+// fire runs the action of the hook for the entry.
+func (s *Scheduler) fire(ctx context.Context, hook config.Hook, result search.Result) error {
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+	log := result.Log
+	moment := log.Moment()
+	switch {
+	case hook.Command != "":
+		env := []string{
+			"BJ_HOOK=" + hook.On,
+			"BJ_ID=" + log.Id,
+			"BJ_NAME=" + log.Name,
+			"BJ_MARK=" + string(log.Mark),
+			"BJ_MOMENT=" + moment.String(),
+			"BJ_DATE=" + result.Date.Format("2006-01-02"),
+		}
+		return s.runner.Run(ctx, env, "sh", "-c", hook.Command)
+	case hook.Fifo != "":
+		return writeFifo(hook.Fifo, fmt.Sprintf("%v\t%v\t%v\t%v\n", hook.On, log.Id, moment, log.Name))
+	case hook.Notify:
+		return s.runner.Run(ctx, nil, "notify-send", notificationTitle(hook), fmt.Sprintf("%v %v", moment, log.Name))
+	}
+	return nil
+}
+
+func notificationTitle(hook config.Hook) string {
+	if hook.On == config.HookOverdue {
+		return "Task overdue"
+	}
+	if hook.Before > 0 {
+		return fmt.Sprintf("Event in %v", hook.Before)
+	}
+	return "Event starting"
+}
+
+// writeFifo writes the line to the named pipe without waiting for a reader,
+// it fails when nobody is reading.
+func writeFifo(p, line string) error {
+	file, err := os.OpenFile(p, os.O_WRONLY|os.O_APPEND|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(line)
+	return err
+}
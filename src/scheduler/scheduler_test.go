@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/config"
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRunner records the programs instead of running them.
+type fakeRunner struct {
+	runs [][]string
+}
+
+func (r *fakeRunner) Run(ctx context.Context, env []string, name string, args ...string) error {
+	r.runs = append(r.runs, append(append([]string{name}, args...), env...))
+	return nil
+}
+
+func TestSchedulerFiresHooks(t *testing.T) {
+	dir := t.TempDir()
+	logService := service.NewLogService(dir)
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	dentist := model.NewLog("Dentist", model.Event)
+	dentist.SetMoment(&model.Moment{Time: date.Add(14 * time.Hour)})
+	rent := model.NewLog("Pay rent", model.Task)
+	rent.SetMoment(&model.Moment{Time: date, AllDay: true})
+	for _, log := range []model.Log{dentist, rent} {
+		_, err := logService.AddLog(date, log)
+		assert.Nil(t, err)
+	}
+
+	fifo := filepath.Join(dir, "hooks")
+	assert.Nil(t, os.WriteFile(fifo, nil, 0644))
+	scheduler := NewScheduler(logService, []config.Hook{
+		{On: config.HookStart, Before: 10 * time.Minute, Notify: true},
+		{On: config.HookStart, Fifo: fifo},
+		{On: config.HookOverdue, Command: "echo $BJ_NAME"},
+	})
+	runner := &fakeRunner{}
+	scheduler.runner = runner
+	now := date.Add(13 * time.Hour)
+	scheduler.now = func() time.Time { return now }
+	scheduler.last = now
+	step := func(to time.Time) {
+		now = to
+		scheduler.check(context.Background())
+	}
+
+	step(date.Add(13*time.Hour + 49*time.Minute))
+	assert.Empty(t, runner.runs)
+
+	// The notification comes 10 minutes before the event.
+	step(date.Add(13*time.Hour + 50*time.Minute))
+	assert.Len(t, runner.runs, 1)
+	assert.Equal(t, []string{"notify-send", "Event in 10m0s", "2026-10-18 14:00 Dentist"}, runner.runs[0])
+
+	step(date.Add(14*time.Hour + 30*time.Second))
+	assert.Len(t, runner.runs, 1)
+	line, err := os.ReadFile(fifo)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(line), "start\t"+dentist.Id))
+
+	// The task goes overdue at the end of the day it was due, only once.
+	step(date.AddDate(0, 0, 1).Add(time.Minute))
+	assert.Len(t, runner.runs, 2)
+	assert.Equal(t, []string{"sh", "-c", "echo $BJ_NAME"}, runner.runs[1][:3])
+	assert.Contains(t, runner.runs[1], "BJ_NAME=Pay rent")
+	assert.Contains(t, runner.runs[1], "BJ_HOOK=overdue")
+	step(date.AddDate(0, 0, 1).Add(2 * time.Minute))
+	assert.Len(t, runner.runs, 2)
+}
@@ -0,0 +1,247 @@
+// Package search parses the queries of the journal search and matches them
+// against the logs.
+//
+// A query is a list of terms, all of them must match:
+//
+//	mark:task             the mark of the log, repeated marks match any of them
+//	important:true        important or not important logs
+//	text:"invoice"        text in the name or the linked markdown, a bare word or
+//	                      a quoted phrase is the same
+//	after:2026-09-01      logs of that day or later
+//	before:2026-10-01     logs of that day or earlier
+//	#tag                  logs with the tag, also tag:work
+//	@context              logs with the context, also context:home
+//
+// The text is compared without case. The dates accept the same values as
+// timeconv.ParseDate.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+)
+
+// ErrInvalidQuery is returned when a query can't be parsed.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Query is a parsed search query.
+type Query struct {
+	Marks     []model.Category
+	Important *bool
+	Texts     []string
+	Tags      []string
+	Contexts  []string
+	After     *time.Time
+	Before    *time.Time
+}
+
+// Result is a log that matches a query and the day it belongs to.
+type Result struct {
+	Date time.Time
+	Log  model.Log
+}
+
+// Parse parses the query, the relative dates are resolved from now.
+func Parse(text string, now time.Time) (Query, error) {
+	terms, err := tokenize(text)
+	if err != nil {
+		return Query{}, err
+	}
+	var query Query
+	for _, term := range terms {
+		key, value, found := cut(term, ':')
+		if !found || term.quoted {
+			if strings.HasPrefix(term.text, "#") && !term.quoted && len(term.text) > 1 {
+				query.Tags = append(query.Tags, strings.ToLower(term.text[1:]))
+			} else if strings.HasPrefix(term.text, "@") && !term.quoted && len(term.text) > 1 {
+				query.Contexts = append(query.Contexts, strings.ToLower(term.text[1:]))
+			} else {
+				query.Texts = append(query.Texts, strings.ToLower(term.text))
+			}
+			continue
+		}
+		switch key {
+		case "mark":
+			mark := model.Category(strings.ToLower(value))
+			if !mark.IsValid() {
+				return Query{}, fmt.Errorf("%w: unknown mark %q", ErrInvalidQuery, value)
+			}
+			query.Marks = append(query.Marks, mark)
+		case "important":
+			switch strings.ToLower(value) {
+			case "true", "yes":
+				important := true
+				query.Important = &important
+			case "false", "no":
+				important := false
+				query.Important = &important
+			default:
+				return Query{}, fmt.Errorf("%w: important must be true or false", ErrInvalidQuery)
+			}
+		case "text":
+			query.Texts = append(query.Texts, strings.ToLower(value))
+		case "after", "before":
+			date, err := timeconv.ParseDate(value, now)
+			if err != nil {
+				return Query{}, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+			}
+			if key == "after" {
+				query.After = &date
+			} else {
+				query.Before = &date
+			}
+		case "tag":
+			query.Tags = append(query.Tags, strings.ToLower(strings.TrimPrefix(value, "#")))
+		case "context":
+			query.Contexts = append(query.Contexts, strings.ToLower(strings.TrimPrefix(value, "@")))
+		default:
+			return Query{}, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, key)
+		}
+	}
+	return query, nil
+}
+
+// MatchesDay checks if the day is in the dates of the query, the days out of
+// them don't need to be read.
+func (q *Query) MatchesDay(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if q.After != nil && day.Before(time.Date(q.After.Year(), q.After.Month(), q.After.Day(), 0, 0, 0, 0, time.UTC)) {
+		return false
+	}
+	if q.Before != nil && day.After(time.Date(q.Before.Year(), q.Before.Month(), q.Before.Day(), 0, 0, 0, 0, time.UTC)) {
+		return false
+	}
+	return true
+}
+
+// Matches checks the log against the query, without its sub logs.
+func (q *Query) Matches(log *model.Log) bool {
+	if len(q.Marks) > 0 {
+		found := false
+		for _, mark := range q.Marks {
+			if log.Mark == mark {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Important != nil && log.Important != *q.Important {
+		return false
+	}
+	if len(q.Texts) > 0 {
+		content := strings.ToLower(log.Name)
+		if log.Text != nil {
+			content += "\n" + strings.ToLower(*log.Text)
+		}
+		for _, text := range q.Texts {
+			if !strings.Contains(content, text) {
+				return false
+			}
+		}
+	}
+	if len(q.Tags) == 0 && len(q.Contexts) == 0 {
+		return true
+	}
+	// The tags of the linked file count as tags of the log.
+	tags, contexts := log.Tags, log.Contexts
+	if log.Text != nil {
+		textTags, textContexts := model.ParseTags(*log.Text)
+		tags = append(append([]string{}, tags...), textTags...)
+		contexts = append(append([]string{}, contexts...), textContexts...)
+	}
+	return containsAll(tags, q.Tags) && containsAll(contexts, q.Contexts)
+}
+
+// Search returns the logs of the day, at any depth, that match the query.
+func (q *Query) Search(dailyLog *model.DailyLog) []Result {
+	if !q.MatchesDay(dailyLog.Date) {
+		return nil
+	}
+	var results []Result
+	var walk func(logs []model.Log)
+	walk = func(logs []model.Log) {
+		for i := range logs {
+			if q.Matches(&logs[i]) {
+				results = append(results, Result{Date: dailyLog.Date, Log: logs[i].Clone()})
+			}
+			if logs[i].SubLogs != nil {
+				walk(*logs[i].SubLogs)
+			}
+		}
+	}
+	walk(dailyLog.Logs)
+	return results
+}
+
+// containsAll checks if every one of the wanted values is in the values.
+func containsAll(values, wanted []string) bool {
+	for _, want := range wanted {
+		found := false
+		for _, value := range values {
+			if value == want {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+// cut splits a field:value term, a quoted value keeps its spaces.
+func cut(term token, separator byte) (string, string, bool) {
+	index := strings.IndexByte(term.text, separator)
+	if index <= 0 {
+		return "", "", false
+	}
+	return strings.ToLower(term.text[:index]), term.text[index+1:], true
+}
+
+// tokenize splits the query in terms by spaces, except inside quotes. A quoted
+// term alone is a phrase, while a quoted value of a field is just the value.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	var current strings.Builder
+	quoted, inQuotes, started := false, false, false
+	flush := func() {
+		if started {
+			tokens = append(tokens, token{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		quoted, started = false, false
+	}
+	for _, r := range text {
+		switch {
+		case r == '"':
+			if !inQuotes && !started {
+				quoted = true
+			}
+			inQuotes = !inQuotes
+			started = true
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("%w: unclosed quote", ErrInvalidQuery)
+	}
+	flush()
+	return tokens, nil
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	query, err := Parse(`mark:task important:true text:"the invoice" after:2026-09-01 before:yesterday #Work "due date" call`, now)
+	assert.Nil(t, err)
+	assert.Equal(t, []model.Category{model.Task}, query.Marks)
+	assert.True(t, *query.Important)
+	assert.Equal(t, []string{"the invoice", "due date", "call"}, query.Texts)
+	assert.Equal(t, []string{"work"}, query.Tags)
+	assert.Equal(t, time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC), *query.After)
+	assert.Equal(t, time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), *query.Before)
+
+	for _, text := range []string{`mark:unknown`, `important:maybe`, `after:someday`, `color:red`, `text:"open`} {
+		_, err := Parse(text, now)
+		assert.ErrorIs(t, err, ErrInvalidQuery, text)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	body := "Pay the INVOICE before friday"
+	task := model.NewLog("Call the bank #work", model.Task)
+	task.Important = true
+	task.Text = &body
+	task.AppendNewSubLog("Ask about the invoice", model.Note)
+	dailyLog := model.NewDailyLog("16.10.2026", "")
+	dailyLog.Logs = []model.Log{task, model.NewLog("Lunch #workshop @office", model.Event)}
+
+	search := func(text string) []string {
+		query, err := Parse(text, time.Now())
+		assert.Nil(t, err)
+		var names []string
+		for _, result := range query.Search(&dailyLog) {
+			names = append(names, result.Log.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"Call the bank #work", "Ask about the invoice"}, search("invoice"))
+	assert.Equal(t, []string{"Call the bank #work"}, search("#work"))
+	assert.Equal(t, []string{"Lunch #workshop @office"}, search("mark:event mark:note lunch"))
+	assert.Equal(t, []string{"Lunch #workshop @office"}, search("@office #workshop"))
+	assert.Equal(t, []string{"Lunch #workshop @office"}, search("context:Office"))
+	assert.Empty(t, search("@work"))
+	assert.Equal(t, []string{"Call the bank #work"}, search("important:true"))
+	assert.Equal(t, []string{"Call the bank #work"}, search("after:2026-10-16 before:2026-10-16 mark:task"))
+	assert.Empty(t, search("after:2026-10-17"))
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+)
+
+// Agenda returns the logs due or happening from the start of the day of from
+// to the end of the day of to, at any depth, sorted by their moments. The
+// migrated, scheduled and irrelevant logs are left out, their copies are the
+// ones that count.
+func (m *LogService) Agenda(from, to time.Time) ([]search.Result, error) {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	return m.momentLogs(func(day time.Time) bool {
+		return !day.Before(start) && day.Before(end)
+	}, func(log *model.Log) bool {
+		moment := log.Moment()
+		return moment != nil && !moment.Time.Before(start) && moment.Time.Before(end) &&
+			!log.IsMigrated() && !log.IsScheduled() && !log.IsIrrelevant()
+	})
+}
+
+// Overdue returns the tasks still open after they were due, the oldest first.
+func (m *LogService) Overdue(now time.Time) ([]search.Result, error) {
+	return m.momentLogs(func(day time.Time) bool {
+		return !day.After(now)
+	}, func(log *model.Log) bool {
+		return log.IsOverdue(now)
+	})
+}
+
+// momentLogs returns the logs that match of the days with moments on the days
+// accepted by days, sorted by their moments.
+func (m *LogService) momentLogs(days func(day time.Time) bool, match func(log *model.Log) bool) ([]search.Result, error) {
+	index, err := m.readJournalIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer m.mx.RUnlock()
+	candidates := index.matchingDays(keyMoment, func(value string) bool {
+		day, err := timeconv.StringToDayTime(value)
+		return err == nil && days(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local))
+	})
+	var results []search.Result
+	var walk func(date time.Time, logs []model.Log)
+	walk = func(date time.Time, logs []model.Log) {
+		for i := range logs {
+			if match(&logs[i]) {
+				results = append(results, search.Result{Date: date, Log: logs[i].Clone()})
+			}
+			if logs[i].SubLogs != nil {
+				walk(date, *logs[i].SubLogs)
+			}
+		}
+	}
+	for _, dailyLog := range m.readIndexedDays(m.toDates(candidates)) {
+		walk(dailyLog.Date, dailyLog.Logs)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Log.Moment(), results[j].Log.Moment()
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		// The all day moments before the ones with a time.
+		if a.AllDay != b.AllDay {
+			return a.AllDay
+		}
+		return results[i].Date.Before(results[j].Date)
+	})
+	return results, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgenda(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	day := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.Local)
+	add := func(date time.Time, text string, mark model.Category) string {
+		name, moment, err := model.ParseMomentText(text, date)
+		assert.Nil(t, err)
+		log := model.NewLog(name, mark)
+		log.SetMoment(moment)
+		dailyLog, err := logService.AddLog(date, log)
+		assert.Nil(t, err)
+		return dailyLog.Logs[len(dailyLog.Logs)-1].Id
+	}
+	report := add(day.AddDate(0, 0, -3), "Report ^2026-10-15", model.Task)
+	add(day, "Dentist ^2026-10-20 14:00", model.Event)
+	add(day, "Standup ^9:30", model.Event)
+	add(day, "Taxes ^2026-10-20", model.Task)
+	add(day, "No date", model.Task)
+
+	agenda, err := logService.Agenda(day, day.AddDate(0, 0, 7))
+	assert.Nil(t, err)
+	var got []string
+	for _, result := range agenda {
+		got = append(got, result.Log.Name)
+	}
+	assert.Equal(t, []string{"Standup", "Taxes", "Dentist"}, got)
+
+	now := day.Add(10 * time.Hour)
+	overdue, err := logService.Overdue(now)
+	assert.Nil(t, err)
+	assert.Len(t, overdue, 1)
+	assert.Equal(t, "Report", overdue[0].Log.Name)
+
+	// The migrated copy keeps the due date and is the one overdue.
+	dailyLog, err := logService.MigrateLog(report, day)
+	assert.Nil(t, err)
+	assert.Equal(t, "2026-10-15", dailyLog.Logs[len(dailyLog.Logs)-1].Due.String())
+	overdue, err = logService.Overdue(now)
+	assert.Nil(t, err)
+	assert.Len(t, overdue, 1)
+	assert.Equal(t, "17.10.2026", timeconv.TimeToDayString(overdue[0].Date))
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+)
+
+const (
+	// metaDir is the directory of the journal with the files of the
+	// application that are not part of the journal.
+	metaDir    = ".bjournal"
+	backupsDir = "backups"
+)
+
+// ErrBackupNotFound is returned when the requested backup doesn't exist.
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup is a previous version of a journal file.
+type Backup struct {
+	// Generation is 1 for the most recent backup.
+	Generation int
+	ModTime    time.Time
+	Path       string
+}
+
+// SetBackups sets the number of previous versions kept for each file, 0
+// disables the backups.
+func (m *LogService) SetBackups(generations int) *LogService {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.backups = generations
+	return m
+}
+
+func (m *LogService) backupPath(filePath string, generation int) string {
+	return path.Join(m.baseDir, metaDir, backupsDir, fmt.Sprintf("%v.%v", filepath.Base(filePath), generation))
+}
+
+// writeFile keeps a backup of the file and replaces it atomically, adding it
+// to the recorded command. The lock must be held.
+func (m *LogService) writeFile(filePath string, data []byte) error {
+	m.recordWrite(filePath, data)
+	if err := m.rotateBackups(filePath); err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(filePath, data, 0644)
+}
+
+// rotateBackups moves every backup of the file one generation back and copies
+// the file as the first generation.
+func (m *LogService) rotateBackups(filePath string) error {
+	if m.backups <= 0 {
+		return nil
+	}
+	current, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(m.baseDir, metaDir, backupsDir), 0755); err != nil {
+		return err
+	}
+	for generation := m.backups; generation > 1; generation-- {
+		err := os.Rename(m.backupPath(filePath, generation-1), m.backupPath(filePath, generation))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return utils.WriteFileAtomic(m.backupPath(filePath, 1), current, 0644)
+}
+
+// Backups returns the previous versions of the day, the most recent first.
+func (m *LogService) Backups(date time.Time) ([]Backup, error) {
+	m.mx.RLock()
+	defer m.mx.RUnlock()
+	dayPath := m.dayPath(timeconv.TimeToDayString(date))
+	var backups []Backup
+	for generation := 1; generation <= m.backups; generation++ {
+		backupPath := m.backupPath(dayPath, generation)
+		info, err := os.Stat(backupPath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return backups, err
+		}
+		backups = append(backups, Backup{Generation: generation, ModTime: info.ModTime(), Path: backupPath})
+	}
+	return backups, nil
+}
+
+// RestoreDay replaces the day with one of its backups. The replaced version
+// becomes the first generation, so a restore can be undone restoring it.
+func (m *LogService) RestoreDay(date time.Time, generation int) (model.DailyLog, error) {
+	dateString := timeconv.TimeToDayString(date)
+	defer m.command("restore " + dateString)()
+	dayPath := m.dayPath(dateString)
+	data, err := os.ReadFile(m.backupPath(dayPath, generation))
+	if errors.Is(err, os.ErrNotExist) {
+		return model.DailyLog{}, fmt.Errorf("%w: %v generation %v", ErrBackupNotFound, dateString, generation)
+	}
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	if _, err := model.DailyFrom(data, date, dateString, m.baseDir); err != nil {
+		return model.DailyLog{}, fmt.Errorf("the backup is not valid: %w", err)
+	}
+	if err := m.writeFile(dayPath, data); err != nil {
+		return model.DailyLog{}, err
+	}
+	delete(m.cache, dateString)
+	delete(m.dirty, dateString)
+	m.rememberStamp(dateString)
+	m.forgetIndexedDay(dateString)
+	dailyLog, err := m.day(date)
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	return dailyLog.Copy(), nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupsRotateAndRestore(t *testing.T) {
+	logService := NewLogService(t.TempDir()).SetBackups(2)
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		_, err := logService.AddNewLog(date, name, model.Task)
+		assert.Nil(t, err)
+	}
+
+	backups, err := logService.Backups(date)
+	assert.Nil(t, err)
+	assert.Len(t, backups, 2)
+	assert.Equal(t, 1, backups[0].Generation)
+
+	dailyLog, err := logService.RestoreDay(date, 2)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 2)
+
+	dailyLog, err = NewLogService(logService.baseDir).ReadDay(date)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 2)
+
+	// The replaced version is the most recent backup.
+	dailyLog, err = logService.RestoreDay(date, 1)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 4)
+
+	_, err = logService.RestoreDay(date, 3)
+	assert.ErrorIs(t, err, ErrBackupNotFound)
+}
+
+func TestRestoreCanBeUndone(t *testing.T) {
+	logService := NewLogService(t.TempDir()).SetBackups(2)
+	// The index is only updated with the days the service changed.
+	now := time.Now()
+	logService.now = func() time.Time { return now }
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	for _, name := range []string{"first", "second"} {
+		_, err := logService.AddNewLog(date, name, model.Task)
+		assert.Nil(t, err)
+	}
+	dailyLog, err := logService.ReadDay(date)
+	assert.Nil(t, err)
+	dailyLog, err = logService.SaveDay(date, dailyLog.Logs[:1])
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+	query, err := search.Parse("second", time.Now())
+	assert.Nil(t, err)
+	results, err := logService.Search(query)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+
+	_, err = logService.RestoreDay(date, 1)
+	assert.Nil(t, err)
+	results, err = logService.Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+
+	command, err := logService.Undo()
+	assert.Nil(t, err)
+	assert.Equal(t, "restore 18.10.2026", command.Name)
+	results, err = logService.Search(query)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// These tests are meant to be run with the race detector: go test -race ./...
+
+func TestConcurrentWrites(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	parent, err := logService.AddNewLog(date, "parent", model.Task)
+	assert.Nil(t, err)
+	parentId := parent.Logs[0].Id
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dailyLog, err := logService.AddNewLog(date, fmt.Sprintf("task %v", i), model.Task)
+			assert.Nil(t, err)
+			_, err = logService.MarkLog(dailyLog.Logs[len(dailyLog.Logs)-1].Id, model.Complete)
+			assert.Nil(t, err)
+			_, err = logService.AppendNewLog(parentId, date, fmt.Sprintf("sub %v", i), model.Note)
+			assert.Nil(t, err)
+		}(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dailyLog, err := logService.ReadDay(date)
+			assert.Nil(t, err)
+			// The copies can be modified without affecting the service.
+			for i := range dailyLog.Logs {
+				dailyLog.Logs[i].Name = "changed"
+			}
+			_, _, err = logService.FindLog(parentId)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	dailyLog, err := logService.ReadDay(date)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 21)
+	assert.Len(t, *dailyLog.Find(parentId).SubLogs, 20)
+	for _, log := range dailyLog.Logs[1:] {
+		assert.Equal(t, model.Complete, log.Mark)
+		assert.NotEqual(t, "changed", log.Name)
+	}
+
+	reloaded, err := NewLogService(logService.baseDir).ReadDay(date)
+	assert.Nil(t, err)
+	assert.Len(t, reloaded.Logs, 21)
+}
+
+func TestConcurrentMigrations(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	yesterday := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.Local)
+	today := yesterday.AddDate(0, 0, 1)
+	var ids []string
+	for i := 0; i < 10; i++ {
+		dailyLog, err := logService.AddNewLog(yesterday, fmt.Sprintf("task %v", i), model.Task)
+		assert.Nil(t, err)
+		ids = append(ids, dailyLog.Logs[i].Id)
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(2)
+		go func(id string) {
+			defer wg.Done()
+			_, err := logService.MigrateLog(id, today)
+			assert.Nil(t, err)
+		}(id)
+		go func() {
+			defer wg.Done()
+			_, err := logService.GetPreviousDate(today)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	previous, err := logService.ReadDay(yesterday)
+	assert.Nil(t, err)
+	for _, log := range previous.Logs {
+		assert.Equal(t, model.Migrated, log.Mark)
+	}
+	migrated, err := logService.ReadDay(today)
+	assert.Nil(t, err)
+	assert.Len(t, migrated.Logs, 10)
+}
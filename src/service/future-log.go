@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+)
+
+// ErrNotFuture is returned when a log is scheduled for a date that is not in
+// the future.
+var ErrNotFuture = errors.New("the date is not in the future")
+
+// ReadFuture returns the future log of the year.
+func (m *LogService) ReadFuture(year int) (model.FutureLog, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	futureLog, err := m.future(year)
+	if err != nil {
+		return model.FutureLog{}, err
+	}
+	return futureLog.Copy(), nil
+}
+
+// future returns the cached future log of the year, reading it when needed.
+// The lock must be held.
+func (m *LogService) future(year int) (*model.FutureLog, error) {
+	futureLog := model.NewFutureLog(year)
+	if cached, ok := m.futures[futureLog.Key()]; ok {
+		return cached, nil
+	}
+	data, err := os.ReadFile(path.Join(m.baseDir, fmt.Sprintf("%v.yaml", futureLog.Key())))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		futureLog, err = model.FutureFrom(data, year)
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.futures[futureLog.Key()] = &futureLog
+	return &futureLog, nil
+}
+
+// updateFutureLocked applies the changes to a copy of the future log of the
+// year, and when they succeed stores the copy in the cache and in its file.
+// The lock must be held.
+func (m *LogService) updateFutureLocked(year int, changes func(futureLog *model.FutureLog) error) (model.FutureLog, error) {
+	cached, err := m.future(year)
+	if err != nil {
+		return model.FutureLog{}, err
+	}
+	futureLog := cached.Copy()
+	if err := changes(&futureLog); err != nil {
+		return cached.Copy(), err
+	}
+	bytes, err := futureLog.ToBytes()
+	if err != nil {
+		return cached.Copy(), err
+	}
+	if err := m.writeFile(path.Join(m.baseDir, fmt.Sprintf("%v.yaml", futureLog.Key())), bytes); err != nil {
+		return cached.Copy(), err
+	}
+	m.futures[futureLog.Key()] = &futureLog
+	return futureLog.Copy(), nil
+}
+
+// ScheduleLog moves the log with the given id to the future log and marks the
+// original as scheduled. When wholeMonth is true the log is scheduled for the
+// month of the date instead of the day, and it shows up in the daily log of
+// the first day of that month.
+func (m *LogService) ScheduleLog(id string, date time.Time, wholeMonth bool) (model.FutureLog, error) {
+	defer m.command("schedule entry")()
+	return m.scheduleLogLocked(id, date, wholeMonth)
+}
+
+// scheduleLogLocked is ScheduleLog when the lock is already held.
+func (m *LogService) scheduleLogLocked(id string, date time.Time, wholeMonth bool) (model.FutureLog, error) {
+	now := m.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, date.Location())
+	if wholeMonth {
+		date = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		today = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, date.Location())
+	}
+	if !date.After(today) {
+		return model.FutureLog{}, fmt.Errorf("%w: %v", ErrNotFuture, date.Format("2006-01-02"))
+	}
+	from, log, err := m.findLog(id)
+	if err != nil {
+		return model.FutureLog{}, err
+	}
+	if !log.IsATask() {
+		return model.FutureLog{}, fmt.Errorf("%w: %v can't be scheduled", ErrInvalidMark, log.Mark)
+	}
+	futureLog, err := m.updateFutureLocked(date.Year(), func(futureLog *model.FutureLog) error {
+		futureLog.Schedule(date, wholeMonth, from.Key(), log.Clone())
+		return nil
+	})
+	if err != nil {
+		return model.FutureLog{}, err
+	}
+	_, err = m.updateLocked(from.Date, func(dailyLog *model.DailyLog) error {
+		dailyLog.Find(id).MarkAsScheduled()
+		return nil
+	})
+	return futureLog, err
+}
+
+// surfaceLocked adds to the day the logs of the future log that are due and
+// were not added yet, including the ones of the previous year that were
+// missed. The lock must be held.
+func (m *LogService) surfaceLocked(date time.Time) error {
+	for _, year := range []int{date.Year() - 1, date.Year()} {
+		futureLog, err := m.future(year)
+		if err != nil {
+			return err
+		}
+		due := futureLog.Due(date)
+		if len(due) == 0 {
+			continue
+		}
+		var logs []model.Log
+		for _, i := range due {
+			log := futureLog.Entries[i].Log.Clone()
+			log.RenewIds()
+			logs = append(logs, log)
+		}
+		// The day is saved first, a failure marking the entries repeats them
+		// instead of losing them.
+		_, err = m.updateLocked(date, func(dailyLog *model.DailyLog) error {
+			dailyLog.Logs = append(dailyLog.Logs, logs...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		_, err = m.updateFutureLocked(year, func(futureLog *model.FutureLog) error {
+			for _, i := range due {
+				futureLog.Entries[i].Surfaced = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
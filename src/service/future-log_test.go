@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleAndSurface(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	today := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	logService.now = func() time.Time { return today }
+
+	dailyLog, err := logService.AddNewLog(today, "Book flights", model.Task)
+	assert.Nil(t, err)
+	task := dailyLog.Logs[0]
+
+	_, err = logService.ScheduleLog(task.Id, today, false)
+	assert.ErrorIs(t, err, ErrNotFuture)
+	_, err = logService.ScheduleLog(task.Id, today, true)
+	assert.ErrorIs(t, err, ErrNotFuture)
+
+	due := time.Date(2027, time.January, 5, 0, 0, 0, 0, time.Local)
+	futureLog, err := logService.ScheduleLog(task.Id, due, false)
+	assert.Nil(t, err)
+	assert.Equal(t, time.January, futureLog.Entries[0].Month)
+	assert.Equal(t, 5, futureLog.Entries[0].Day)
+	_, log, err := logService.FindLog(task.Id)
+	assert.Nil(t, err)
+	assert.True(t, log.IsScheduled())
+
+	// A new process on the date finds the entry in the daily log, only once.
+	for i := 0; i < 2; i++ {
+		reopened := NewLogService(logService.baseDir)
+		reopened.now = func() time.Time { return due.Add(9 * time.Hour) }
+		dailyLog, err = reopened.ReadDay(due)
+		assert.Nil(t, err)
+		assert.Len(t, dailyLog.Logs, 1)
+		assert.Equal(t, "Book flights", dailyLog.Logs[0].Name)
+		assert.True(t, dailyLog.Logs[0].IsATask())
+	}
+
+	futureLog, err = logService.ReadFuture(2027)
+	assert.Nil(t, err)
+	assert.False(t, futureLog.Entries[0].Surfaced)
+	futureLog, err = NewLogService(logService.baseDir).ReadFuture(2027)
+	assert.Nil(t, err)
+	assert.True(t, futureLog.Entries[0].Surfaced)
+}
+
+func TestMissedEntriesOfThePreviousYearSurface(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	logService.now = func() time.Time { return time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local) }
+	dailyLog, err := logService.AddNewLog(logService.now(), "Renew domain", model.Task)
+	assert.Nil(t, err)
+	_, err = logService.ScheduleLog(dailyLog.Logs[0].Id, time.Date(2026, time.December, 1, 0, 0, 0, 0, time.Local), true)
+	assert.Nil(t, err)
+
+	reopened := NewLogService(logService.baseDir)
+	later := time.Date(2027, time.February, 2, 0, 0, 0, 0, time.Local)
+	reopened.now = func() time.Time { return later }
+	dailyLog, err = reopened.ReadDay(later)
+	assert.Nil(t, err)
+	assert.Equal(t, "Renew domain", dailyLog.Logs[0].Name)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+)
+
+const habitsFile = "habits.yaml"
+
+var (
+	// ErrHabitNotFound is returned when there is no habit with the name.
+	ErrHabitNotFound = errors.New("habit not found")
+	// ErrHabitExists is returned when a habit is added with the name of
+	// another one.
+	ErrHabitExists = errors.New("the habit already exists")
+)
+
+func (m *LogService) habitsPath() string {
+	return path.Join(m.baseDir, habitsFile)
+}
+
+// ReadHabits returns the habits of the journal.
+func (m *LogService) ReadHabits() (model.HabitLog, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return m.habits()
+}
+
+// habits reads the habits file, it is not cached so the changes made by hand
+// are seen. The lock must be held.
+func (m *LogService) habits() (model.HabitLog, error) {
+	data, err := os.ReadFile(m.habitsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return model.HabitLog{Entries: []model.Habit{}}, nil
+	}
+	if err != nil {
+		return model.HabitLog{}, err
+	}
+	habitLog, err := model.HabitsFrom(data)
+	if err != nil {
+		return habitLog, fmt.Errorf("parsing %v: %w", habitsFile, err)
+	}
+	return habitLog, nil
+}
+
+func (m *LogService) writeHabits(habitLog model.HabitLog) error {
+	bytes, err := habitLog.ToBytes()
+	if err != nil {
+		return err
+	}
+	return m.writeFile(m.habitsPath(), bytes)
+}
+
+// AddHabit stores the habit.
+func (m *LogService) AddHabit(habit model.Habit) (model.HabitLog, error) {
+	if err := habit.Validate(); err != nil {
+		return model.HabitLog{}, err
+	}
+	defer m.command(fmt.Sprintf("add habit %q", habit.Name))()
+	habitLog, err := m.habits()
+	if err != nil {
+		return habitLog, err
+	}
+	if _, ok := habitLog.Find(habit.Name); ok {
+		return habitLog, fmt.Errorf("%w: %v", ErrHabitExists, habit.Name)
+	}
+	habitLog.Entries = append(habitLog.Entries, habit)
+	return habitLog, m.writeHabits(habitLog)
+}
+
+// DeleteHabit removes the habit, its check-ins are kept in the days.
+func (m *LogService) DeleteHabit(name string) (model.HabitLog, error) {
+	defer m.command(fmt.Sprintf("delete habit %q", name))()
+	habitLog, err := m.habits()
+	if err != nil {
+		return habitLog, err
+	}
+	if !habitLog.Remove(name) {
+		return habitLog, fmt.Errorf("%w: %v", ErrHabitNotFound, name)
+	}
+	return habitLog, m.writeHabits(habitLog)
+}
+
+// CheckHabit marks the habit as done on the day of the date, or as not done.
+func (m *LogService) CheckHabit(date time.Time, name string, done bool) (model.Habit, error) {
+	command := "check habit"
+	if !done {
+		command = "uncheck habit"
+	}
+	defer m.command(command)()
+	habitLog, err := m.habits()
+	if err != nil {
+		return model.Habit{}, err
+	}
+	habit, ok := habitLog.Find(name)
+	if !ok {
+		return habit, fmt.Errorf("%w: %v", ErrHabitNotFound, name)
+	}
+	cached, err := m.day(date)
+	if err != nil {
+		return habit, err
+	}
+	if dailyLog := cached.Copy(); !dailyLog.CheckHabit(habit.Name, done) {
+		return habit, nil
+	}
+	_, err = m.updateLocked(date, func(dailyLog *model.DailyLog) error {
+		dailyLog.CheckHabit(habit.Name, done)
+		return nil
+	})
+	return habit, err
+}
+
+// HabitCheckIns returns the days every habit was done, by habit name.
+func (m *LogService) HabitCheckIns() (map[string]model.CheckIns, error) {
+	index, err := m.readJournalIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer m.mx.RUnlock()
+	checkIns := map[string]model.CheckIns{}
+	for name, days := range index.lookup[keyHabit] {
+		checkIns[name] = model.CheckIns{}
+		for dateString := range days {
+			checkIns[name][dateString] = true
+		}
+	}
+	return checkIns, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHabits(t *testing.T) {
+	dir := t.TempDir()
+	logService := NewLogService(dir)
+	today := time.Date(2026, time.October, 21, 0, 0, 0, 0, time.Local)
+	yesterday := today.AddDate(0, 0, -1)
+	run, err := model.NewHabit("Run", "3/week")
+	assert.Nil(t, err)
+	_, err = logService.AddHabit(run)
+	assert.Nil(t, err)
+	_, err = logService.AddHabit(model.Habit{Name: "run", Times: 1, Per: model.PerDay})
+	assert.ErrorIs(t, err, ErrHabitExists)
+
+	// The name is matched ignoring the case and stored as in the habit.
+	habit, err := logService.CheckHabit(today, "RUN", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "Run", habit.Name)
+	_, err = logService.CheckHabit(yesterday, "run", true)
+	assert.Nil(t, err)
+	_, err = logService.CheckHabit(today, "run", true)
+	assert.Nil(t, err)
+	_, err = logService.CheckHabit(today, "Swim", true)
+	assert.ErrorIs(t, err, ErrHabitNotFound)
+
+	dailyLog, err := NewLogService(dir).ReadDay(today)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Run"}, dailyLog.Habits)
+
+	checkIns, err := logService.HabitCheckIns()
+	assert.Nil(t, err)
+	assert.Equal(t, model.CheckIns{
+		timeconv.TimeToDayString(today):     true,
+		timeconv.TimeToDayString(yesterday): true,
+	}, checkIns["Run"])
+
+	_, err = logService.CheckHabit(yesterday, "Run", false)
+	assert.Nil(t, err)
+	checkIns, err = logService.HabitCheckIns()
+	assert.Nil(t, err)
+	assert.Len(t, checkIns["Run"], 1)
+
+	// The check-ins are kept with the days.
+	_, err = logService.DeleteHabit("run")
+	assert.Nil(t, err)
+	_, err = logService.DeleteHabit("run")
+	assert.ErrorIs(t, err, ErrHabitNotFound)
+	checkIns, err = logService.HabitCheckIns()
+	assert.Nil(t, err)
+	assert.Len(t, checkIns["Run"], 1)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+)
+
+// MigrationHistory returns the copies of the log along its migrations, from
+// the day it was written to the last day it was migrated to, with their
+// dates. The chain stops at the logs that can't be found anymore.
+func (m *LogService) MigrationHistory(id string) ([]search.Result, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	from, log, err := m.findLog(id)
+	if err != nil {
+		return nil, err
+	}
+	history := []search.Result{{Date: from.Date, Log: log.Clone()}}
+	seen := map[string]bool{id: true}
+	for link := log.MigratedFrom; link != nil && !seen[link.Id]; {
+		seen[link.Id] = true
+		dailyLog, previous, err := m.findLinked(*link)
+		if errors.Is(err, ErrLogNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		history = append([]search.Result{{Date: dailyLog.Date, Log: previous.Clone()}}, history...)
+		link = previous.MigratedFrom
+	}
+	for link := log.MigratedTo; link != nil && !seen[link.Id]; {
+		seen[link.Id] = true
+		dailyLog, next, err := m.findLinked(*link)
+		if errors.Is(err, ErrLogNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, search.Result{Date: dailyLog.Date, Log: next.Clone()})
+		link = next.MigratedTo
+	}
+	return history, nil
+}
+
+// findLinked returns the day and the log of the link, looking for it in the
+// whole journal when it is not in the day of the link anymore. The lock must
+// be held.
+func (m *LogService) findLinked(link model.LogLink) (*model.DailyLog, model.Log, error) {
+	if date, err := timeconv.StringToDayTime(link.Date); err == nil {
+		dailyLog, err := m.day(date)
+		if err != nil {
+			return nil, model.Log{}, err
+		}
+		if log := dailyLog.Find(link.Id); log != nil {
+			return dailyLog, *log, nil
+		}
+	}
+	return m.findLog(link.Id)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationHistory(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	first := time.Date(2026, time.October, 10, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 1)
+	third := first.AddDate(0, 0, 2)
+
+	dailyLog, err := logService.AddNewLog(first, "Fix the bike", model.Task)
+	assert.Nil(t, err)
+	bike := dailyLog.Logs[0].Id
+	dailyLog, err = logService.AppendNewLog(bike, first, "Buy a tube", model.Task)
+	assert.Nil(t, err)
+	tube := (*dailyLog.Logs[0].SubLogs)[0].Id
+
+	dailyLog, err = logService.MigrateLog(bike, second)
+	assert.Nil(t, err)
+	copied := dailyLog.Logs[0]
+	assert.Equal(t, &model.LogLink{Date: timeconv.TimeToDayString(first), Id: bike}, copied.MigratedFrom)
+	assert.Equal(t, 1, copied.Migrations)
+	subLog := (*copied.SubLogs)[0]
+	assert.Equal(t, &model.LogLink{Date: timeconv.TimeToDayString(first), Id: tube}, subLog.MigratedFrom)
+
+	original, err := logService.ReadDay(first)
+	assert.Nil(t, err)
+	assert.Equal(t, &model.LogLink{Date: timeconv.TimeToDayString(second), Id: copied.Id}, original.Logs[0].MigratedTo)
+	assert.Equal(t, &model.LogLink{Date: timeconv.TimeToDayString(second), Id: subLog.Id}, (*original.Logs[0].SubLogs)[0].MigratedTo)
+
+	dailyLog, err = logService.MigrateDay(second, third)
+	assert.Nil(t, err)
+	last := dailyLog.Logs[0]
+	assert.Equal(t, 2, last.Migrations)
+
+	for _, id := range []string{bike, copied.Id, last.Id} {
+		history, err := logService.MigrationHistory(id)
+		assert.Nil(t, err)
+		var dates []string
+		for _, step := range history {
+			dates = append(dates, timeconv.TimeToDayString(step.Date))
+		}
+		assert.Equal(t, []string{"10.10.2026", "11.10.2026", "12.10.2026"}, dates)
+		assert.Equal(t, []string{bike, copied.Id, last.Id}, []string{history[0].Log.Id, history[1].Log.Id, history[2].Log.Id})
+	}
+
+	// A log that was never migrated is its own history.
+	dailyLog, err = logService.AddNewLog(third, "Call mum", model.Task)
+	assert.Nil(t, err)
+	history, err := logService.MigrationHistory(dailyLog.Logs[1].Id)
+	assert.Nil(t, err)
+	assert.Len(t, history, 1)
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	zerolog "github.com/rs/zerolog/log"
+)
+
+const (
+	journalIndexFile = "index.json"
+	// journalIndexVersion changes when the format of the index changes, an
+	// index with another version is rebuilt.
+	journalIndexVersion = 4
+	// journalIndexCheckInterval is the minimum time between two comparisons
+	// of the index with every file of the journal. The changes of the service
+	// and the ones reported by the watcher are indexed right away.
+	journalIndexCheckInterval = 5 * time.Second
+)
+
+// The kinds of the keys the logs are looked up by.
+const (
+	keyId        = "id"
+	keyMark      = "mark"
+	keyImportant = "important"
+	keyTag       = "tag"
+	keyContext   = "context"
+	keyToken     = "token"
+	// keyMoment is the day a log is due or happens on.
+	keyMoment = "moment"
+	keyHabit  = "habit"
+)
+
+// indexedStamp is the stored version of a file.
+type indexedStamp struct {
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+func indexedStampOf(stamp fileStamp) indexedStamp {
+	return indexedStamp{ModTime: stamp.modTime, Size: stamp.size}
+}
+
+func (s indexedStamp) matches(stamp fileStamp) bool {
+	return s.ModTime.Equal(stamp.modTime) && s.Size == stamp.size
+}
+
+// dayKeys are the values of every kind of key of the logs of a day, with the
+// number of logs that have each of them.
+type dayKeys map[string]map[string]int
+
+func (k dayKeys) add(kind, value string) {
+	if k[kind] == nil {
+		k[kind] = map[string]int{}
+	}
+	k[kind][value]++
+}
+
+// indexedDay is what the index keeps of a day file: its keys, with the
+// versions of the file and the markdown files linked from it.
+type indexedDay struct {
+	Stamp indexedStamp `json:"stamp"`
+	// Links are the versions of the linked files by url.
+	Links map[string]indexedStamp `json:"links,omitempty"`
+	Keys  dayKeys                 `json:"keys"`
+}
+
+// journalIndex finds the days of the journal by the ids, marks, importance,
+// tags, contexts, words, moments and habits of their logs. It is stored in the
+// .bjournal directory so lookups and searches only read the days that can
+// match. Only the days whose files changed since they were indexed are read
+// again.
+type journalIndex struct {
+	Version int                    `json:"version"`
+	Days    map[string]*indexedDay `json:"days"`
+	// lookup are the days of every key by kind and value, with the number of
+	// logs of the day that have it.
+	lookup map[string]map[string]map[string]int
+	// dates are the days of the index sorted, nil when they must be sorted
+	// again.
+	dates []time.Time
+	// stale are the days to read again on the next refresh.
+	stale map[string]bool
+	// checked is when the index was last compared with every file.
+	checked time.Time
+}
+
+func newJournalIndex() *journalIndex {
+	return &journalIndex{
+		Version: journalIndexVersion,
+		Days:    map[string]*indexedDay{},
+		lookup:  map[string]map[string]map[string]int{},
+		stale:   map[string]bool{},
+	}
+}
+
+// add indexes the day.
+func (i *journalIndex) add(dateString string, day *indexedDay) {
+	i.remove(dateString)
+	i.Days[dateString] = day
+	for kind, values := range day.Keys {
+		if i.lookup[kind] == nil {
+			i.lookup[kind] = map[string]map[string]int{}
+		}
+		for value, count := range values {
+			if i.lookup[kind][value] == nil {
+				i.lookup[kind][value] = map[string]int{}
+			}
+			i.lookup[kind][value][dateString] = count
+		}
+	}
+	i.dates = nil
+}
+
+// remove drops the day from the index.
+func (i *journalIndex) remove(dateString string) {
+	day, ok := i.Days[dateString]
+	if !ok {
+		return
+	}
+	for kind, values := range day.Keys {
+		for value := range values {
+			delete(i.lookup[kind][value], dateString)
+			if len(i.lookup[kind][value]) == 0 {
+				delete(i.lookup[kind], value)
+			}
+		}
+	}
+	delete(i.Days, dateString)
+	i.dates = nil
+}
+
+// days returns the days with the key.
+func (i *journalIndex) days(kind, value string) map[string]int {
+	return i.lookup[kind][value]
+}
+
+// matchingDays returns the days with any value of the kind accepted by match.
+func (i *journalIndex) matchingDays(kind string, match func(value string) bool) map[string]bool {
+	days := map[string]bool{}
+	for value, counts := range i.lookup[kind] {
+		if !match(value) {
+			continue
+		}
+		for dateString := range counts {
+			days[dateString] = true
+		}
+	}
+	return days
+}
+
+// sortedDates returns the days of the index, the oldest first.
+func (i *journalIndex) sortedDates() []time.Time {
+	if i.dates == nil {
+		i.dates = make([]time.Time, 0, len(i.Days))
+		for dateString := range i.Days {
+			if date, err := timeconv.StringToDayTime(dateString); err == nil {
+				i.dates = append(i.dates, date)
+			}
+		}
+		sort.Slice(i.dates, func(a, b int) bool {
+			return i.dates[a].Before(i.dates[b])
+		})
+	}
+	return i.dates
+}
+
+func (m *LogService) journalIndexPath() string {
+	return path.Join(m.baseDir, metaDir, journalIndexFile)
+}
+
+// loadJournalIndex reads the stored index, an index that can't be read is
+// rebuilt. The lock must be held.
+func (m *LogService) loadJournalIndex() *journalIndex {
+	index := newJournalIndex()
+	data, err := os.ReadFile(m.journalIndexPath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			zerolog.Print("Error reading the journal index ", err)
+		}
+		return index
+	}
+	var stored journalIndex
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != journalIndexVersion || stored.Days == nil {
+		return index
+	}
+	for dateString, day := range stored.Days {
+		index.add(dateString, day)
+	}
+	return index
+}
+
+// refreshJournalIndex brings the index up to date and stores it when anything
+// changed. The days changed by the service or reported by the watcher are read
+// again, and every journalIndexCheckInterval the index is compared with all
+// the files to find the changes of other processes. The lock must be held.
+func (m *LogService) refreshJournalIndex() (*journalIndex, error) {
+	if m.journalIndex == nil {
+		m.journalIndex = m.loadJournalIndex()
+	}
+	index := m.journalIndex
+	if now := m.now(); index.checked.IsZero() || now.Sub(index.checked) >= journalIndexCheckInterval {
+		if _, err := os.Stat(m.baseDir); err != nil {
+			return nil, err
+		}
+		current := m.scanStamps()
+		for dateString, day := range index.Days {
+			if stamp, ok := current[dateString]; !ok || !m.indexedDayIsFresh(day, stamp) {
+				index.stale[dateString] = true
+			}
+		}
+		for dateString := range current {
+			if _, ok := index.Days[dateString]; !ok {
+				index.stale[dateString] = true
+			}
+		}
+		index.checked = now
+	}
+	if len(index.stale) == 0 {
+		return index, nil
+	}
+	stale := index.stale
+	index.stale = map[string]bool{}
+	for dateString := range stale {
+		index.remove(dateString)
+		if _, ok := statFile(m.dayPath(dateString)); !ok {
+			continue
+		}
+		day, err := m.indexDay(dateString)
+		if err != nil {
+			zerolog.Print("Error indexing the day ", err)
+			continue
+		}
+		index.add(dateString, day)
+		// Indexing can save the ids of the day, that is already indexed.
+		delete(index.stale, dateString)
+	}
+	if err := m.saveJournalIndex(index); err != nil {
+		zerolog.Print("Error saving the journal index ", err)
+	}
+	return index, nil
+}
+
+// indexedDayIsFresh checks if the day and its linked files didn't change
+// since they were indexed.
+func (m *LogService) indexedDayIsFresh(day *indexedDay, stamp fileStamp) bool {
+	if !day.Stamp.matches(stamp) {
+		return false
+	}
+	for url, linkStamp := range day.Links {
+		current, ok := statFile(path.Join(m.baseDir, url))
+		if !ok || !linkStamp.matches(current) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexDay reads the day file and its linked files. The lock must be held.
+func (m *LogService) indexDay(dateString string) (*indexedDay, error) {
+	date, err := timeconv.StringToDayTime(dateString)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(m.dayPath(dateString))
+	if err != nil {
+		return nil, err
+	}
+	dailyLog, err := model.DailyFrom(data, date, dateString, m.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if dailyLog.IdsFilled() {
+		// Store the new ids before indexing them.
+		cached, err := m.day(date)
+		if err != nil {
+			return nil, err
+		}
+		dailyLog = cached.Copy()
+	}
+	stamp, _ := statFile(m.dayPath(dateString))
+	day := &indexedDay{Stamp: indexedStampOf(stamp), Keys: dayKeys{}}
+	var walk func(logs []model.Log)
+	walk = func(logs []model.Log) {
+		for i := range logs {
+			log := &logs[i]
+			if log.Url != nil {
+				if linkStamp, ok := statFile(path.Join(m.baseDir, *log.Url)); ok {
+					if day.Links == nil {
+						day.Links = map[string]indexedStamp{}
+					}
+					day.Links[*log.Url] = indexedStampOf(linkStamp)
+				}
+			}
+			addLogKeys(day.Keys, log)
+			if log.SubLogs != nil {
+				walk(*log.SubLogs)
+			}
+		}
+	}
+	walk(dailyLog.Logs)
+	for _, habit := range dailyLog.Habits {
+		day.Keys.add(keyHabit, habit)
+	}
+	return day, nil
+}
+
+// addLogKeys adds the keys of the log, without its sub logs. The tags and the
+// words of the linked file count as the ones of the log, as in the searches.
+func addLogKeys(keys dayKeys, log *model.Log) {
+	keys.add(keyId, log.Id)
+	keys.add(keyMark, string(log.Mark))
+	keys.add(keyImportant, strconv.FormatBool(log.Important))
+	if moment := log.Moment(); moment != nil {
+		keys.add(keyMoment, timeconv.TimeToDayString(moment.Time))
+	}
+	tags, contexts := log.Tags, log.Contexts
+	content := log.Name
+	if log.Text != nil {
+		textTags, textContexts := model.ParseTags(*log.Text)
+		tags = append(append([]string{}, tags...), textTags...)
+		contexts = append(append([]string{}, contexts...), textContexts...)
+		content += "\n" + *log.Text
+	}
+	for _, tag := range unique(tags) {
+		keys.add(keyTag, tag)
+	}
+	for _, context := range unique(contexts) {
+		keys.add(keyContext, context)
+	}
+	for _, token := range unique(tokens(content)) {
+		keys.add(keyToken, token)
+	}
+}
+
+// tokens returns the words of the text without case.
+func tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// forgetIndexedDay makes the day be read again on the next refresh, even if
+// the file kept the same version. The lock must be held.
+func (m *LogService) forgetIndexedDay(dateString string) {
+	if m.journalIndex != nil {
+		m.journalIndex.stale[dateString] = true
+	}
+}
+
+func (m *LogService) saveJournalIndex(index *journalIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(m.baseDir, metaDir), 0755); err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(m.journalIndexPath(), data, 0644)
+}
+
+// readJournalIndex refreshes the index and returns it with the read lock held,
+// so the lookups don't block the other readers. The caller must release the
+// read lock when there is no error.
+func (m *LogService) readJournalIndex() (*journalIndex, error) {
+	m.mx.Lock()
+	index, err := m.refreshJournalIndex()
+	m.mx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	m.mx.RLock()
+	return index, nil
+}
+
+// indexedDate returns the date of the day with the log, using the index. The
+// lock must be held.
+func (m *LogService) indexedDate(id string) (time.Time, bool, error) {
+	index, err := m.refreshJournalIndex()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	for dateString := range index.days(keyId, id) {
+		date, err := timeconv.StringToDayTime(dateString)
+		return date, err == nil, err
+	}
+	return time.Time{}, false, nil
+}
+
+// readIndexedDay returns the day from the cache, or from its file without
+// caching it. The read lock must be held.
+func (m *LogService) readIndexedDay(date time.Time) (model.DailyLog, error) {
+	dateString := timeconv.TimeToDayString(date)
+	if dailyLog, ok := m.cache[dateString]; ok {
+		return dailyLog.Copy(), nil
+	}
+	data, err := os.ReadFile(m.dayPath(dateString))
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	return model.DailyFrom(data, date, dateString, m.baseDir)
+}
+
+// readIndexedDays returns the days, the ones that can't be read are skipped.
+// The read lock must be held.
+func (m *LogService) readIndexedDays(dates []time.Time) []model.DailyLog {
+	dailyLogs := make([]model.DailyLog, 0, len(dates))
+	for _, date := range dates {
+		dailyLog, err := m.readIndexedDay(date)
+		if err != nil {
+			zerolog.Print("Error reading the indexed day ", err)
+			continue
+		}
+		dailyLogs = append(dailyLogs, dailyLog)
+	}
+	return dailyLogs
+}
+
+// toDates returns the days of the set, the most recent first. The days with
+// changes that couldn't be saved are added, the index only knows their files.
+// The read lock must be held.
+func (m *LogService) toDates(days map[string]bool) []time.Time {
+	for dateString := range m.dirty {
+		days[dateString] = true
+	}
+	dates := make([]time.Time, 0, len(days))
+	for dateString := range days {
+		if date, err := timeconv.StringToDayTime(dateString); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].After(dates[j])
+	})
+	return dates
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalIndexFollowsTheFiles(t *testing.T) {
+	dir := t.TempDir()
+	logService := NewLogService(dir)
+	date := time.Date(2026, time.October, 5, 0, 0, 0, 0, time.Local)
+	dailyLog, err := logService.AddNewLog(date, "Send invoice", model.Task)
+	assert.Nil(t, err)
+	id := dailyLog.Logs[0].Id
+
+	query, err := search.Parse("invoice", time.Now())
+	assert.Nil(t, err)
+	results, err := logService.Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	_, err = os.Stat(path.Join(dir, ".bjournal", "index.json"))
+	assert.Nil(t, err)
+
+	// Another process finds the log from the stored index.
+	reopened := NewLogService(dir)
+	found, log, err := reopened.FindLog(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "05.10.2026", found.Format("02.01.2006"))
+	assert.Equal(t, "Send invoice", log.Name)
+	assert.Len(t, reopened.cache, 1)
+
+	// Changes of the days and their linked files are indexed again.
+	assert.Nil(t, os.WriteFile(path.Join(dir, "notes.md"), []byte("nothing yet"), 0644))
+	url := "notes.md"
+	note := model.NewLog("Meeting notes", model.Note)
+	note.Url = &url
+	_, err = logService.AddLog(time.Date(2026, time.October, 6, 0, 0, 0, 0, time.Local), note)
+	assert.Nil(t, err)
+	results, err = reopened.Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+
+	assert.Equal(t, map[string]int{"05.10.2026": 1}, reopened.journalIndex.days(keyToken, "invoice"))
+
+	// The files changed by other processes are compared again after the check
+	// interval.
+	later := time.Now().Add(time.Second)
+	assert.Nil(t, os.WriteFile(path.Join(dir, "notes.md"), []byte("invoice paid"), 0644))
+	assert.Nil(t, os.Chtimes(path.Join(dir, "notes.md"), later, later))
+	results, err = reopened.Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	reopened.now = func() time.Time { return time.Now().Add(journalIndexCheckInterval) }
+	results, err = reopened.Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Len(t, reopened.journalIndex.days(keyToken, "invoice"), 2)
+
+	assert.Nil(t, os.Remove(path.Join(dir, "05.10.2026.yaml")))
+	reopened.now = func() time.Time { return time.Now().Add(2 * journalIndexCheckInterval) }
+	results, err = reopened.Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Meeting notes", results[0].Log.Name)
+}
@@ -1,14 +1,17 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path"
-	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/apoloa/bjournal/src/config"
 	"github.com/apoloa/bjournal/src/model"
 	"github.com/apoloa/bjournal/src/utils"
 	"github.com/apoloa/bjournal/src/utils/timeconv"
@@ -17,20 +20,75 @@ import (
 
 const indexFile = "index.yaml"
 
+var (
+	// ErrLogNotFound is returned when a log id doesn't exist.
+	ErrLogNotFound = errors.New("log not found")
+	// ErrInvalidMark is returned when a mark can't be applied to a log.
+	ErrInvalidMark = errors.New("invalid mark")
+	// ErrCantMove is returned when a log can't be moved or indented further.
+	ErrCantMove = errors.New("the log can't be moved")
+	// ErrNoPreviousDay is returned when there is no day file before a date.
+	ErrNoPreviousDay = errors.New("no previous day")
+)
+
+const defaultEditor = "vi"
+
+// LogService reads and writes the journal files.
+//
+// A LogService is safe for concurrent use, the UI and the API share the same
+// instance. The days are cached and every method returns a copy of them, so
+// callers can keep and modify the returned values without locking. Changes in
+// those copies are not stored: the journal is only modified through the
+// methods of the service, which update the cache and the files atomically.
 type LogService struct {
+	mx      sync.RWMutex
 	baseDir string
-	cache   map[string]model.DailyLog
-	Index   model.Index
+	editor  string
+	backups int
+	cache   map[string]*model.DailyLog
+	months  map[string]*model.MonthlyLog
+	futures map[string]*model.FutureLog
+	index   model.Index
+	// journalIndex is the content of every day, loaded on the first lookup.
+	journalIndex *journalIndex
+	// now returns the current time, replaced in the tests.
+	now func() time.Time
+	// stamps are the versions of the day files read or written by the
+	// service, to tell apart the changes of other processes.
+	stamps map[string]fileStamp
+	// dirty are the days with changes that couldn't be saved.
+	dirty map[string]bool
+	// recurred are the days already given the entries of the recurrences.
+	recurred map[string]bool
+	// recording is the command whose files are being written, nil when the
+	// writes are not part of a command.
+	recording *Command
 }
 
 func NewLogService(baseDir string) *LogService {
 	return &LogService{
-		baseDir: baseDir,
-		cache:   make(map[string]model.DailyLog),
-		Index:   readIndex(baseDir),
+		baseDir:  baseDir,
+		editor:   defaultEditor,
+		backups:  config.DefaultBackups,
+		cache:    make(map[string]*model.DailyLog),
+		months:   make(map[string]*model.MonthlyLog),
+		futures:  make(map[string]*model.FutureLog),
+		now:      time.Now,
+		index:    readIndex(baseDir),
+		stamps:   make(map[string]fileStamp),
+		dirty:    make(map[string]bool),
+		recurred: make(map[string]bool),
 	}
 }
 
+// SetEditor sets the command used to open the index items.
+func (m *LogService) SetEditor(editor string) *LogService {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.editor = editor
+	return m
+}
+
 func readIndex(baseDir string) model.Index {
 	indexPath := path.Join(baseDir, indexFile)
 	data, err := os.ReadFile(indexPath)
@@ -46,150 +104,507 @@ func readIndex(baseDir string) model.Index {
 	return index
 }
 
+// ReadDay returns the day. The first time a day up to today is read, the
+// entries of the recurrences that happen on it are added, as a command that
+// can be undone. The days after today are never written.
 func (m *LogService) ReadDay(date time.Time) (model.DailyLog, error) {
 	dateString := timeconv.TimeToDayString(date)
-	if val, ok := m.cache[dateString]; ok {
-		return val, nil
-	} else {
-		dailyLog, err := m.ReadDailyLog(date, dateString)
+	m.mx.RLock()
+	if dailyLog, ok := m.cache[dateString]; ok && (m.recurred[dateString] || m.isFuture(date)) {
+		defer m.mx.RUnlock()
+		return dailyLog.Copy(), nil
+	}
+	m.mx.RUnlock()
+
+	defer m.command("add recurring entries")()
+	if !m.recurred[dateString] && !m.isFuture(date) {
+		m.recurred[dateString] = true
+		recurrenceLog, err := m.recurrences()
+		if err == nil {
+			err = m.recurLocked(date, recurrenceLog)
+		}
 		if err != nil {
-			return model.DailyLog{}, err
+			zerolog.Print("Error adding the recurrences ", err)
 		}
-		m.cache[dateString] = dailyLog
+	}
+	dailyLog, err := m.day(date)
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	return dailyLog.Copy(), nil
+}
+
+// day returns the cached day, reading it when needed. The lock must be held.
+func (m *LogService) day(date time.Time) (*model.DailyLog, error) {
+	dateString := timeconv.TimeToDayString(date)
+	if dailyLog, ok := m.cache[dateString]; ok {
 		return dailyLog, nil
 	}
+	dailyLog, err := m.readDailyLog(date, dateString)
+	if err != nil {
+		return nil, err
+	}
+	m.cache[dateString] = &dailyLog
+	if dateString == timeconv.TimeToDayString(m.now()) {
+		if err := m.surfaceLocked(date); err != nil {
+			zerolog.Print("Error surfacing the future log ", err)
+		}
+	}
+	return m.cache[dateString], nil
 }
 
-func (m *LogService) ReadDailyLog(dateTime time.Time, date string) (model.DailyLog, error) {
-	filePath := path.Join(m.baseDir, fmt.Sprintf("%v.yaml", date))
-	file, err := os.ReadFile(filePath)
+func (m *LogService) readDailyLog(dateTime time.Time, date string) (model.DailyLog, error) {
+	file, err := os.ReadFile(m.dayPath(date))
 	if err != nil {
 		log.Print("Error reading the file")
 		log.Print(err.Error())
 		return model.NewDailyLog(date, m.baseDir), nil
 	}
-	return model.DailyFrom(file, dateTime, date, m.baseDir)
+	dailyLog, err := model.DailyFrom(file, dateTime, date, m.baseDir)
+	if err != nil {
+		return dailyLog, err
+	}
+	m.rememberStamp(date)
+	if dailyLog.IdsFilled() {
+		// Keep the ids of the logs written by older versions.
+		if err := m.writeDailyLog(date, dailyLog); err != nil {
+			zerolog.Print("Error saving the log ids", err)
+		}
+	}
+	return dailyLog, nil
+}
+
+// update applies the changes to a copy of the day, and when they succeed
+// stores the copy in the cache and in its file, as the command with the name.
+func (m *LogService) update(name string, date time.Time, changes func(dailyLog *model.DailyLog) error) (model.DailyLog, error) {
+	defer m.command(name)()
+	return m.updateLocked(date, changes)
+}
+
+// updateLocked is update when the lock is already held.
+func (m *LogService) updateLocked(date time.Time, changes func(dailyLog *model.DailyLog) error) (model.DailyLog, error) {
+	cached, err := m.day(date)
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	dailyLog := cached.Copy()
+	if err := changes(&dailyLog); err != nil {
+		return cached.Copy(), err
+	}
+	dailyLog.ParseTags()
+	m.cache[dailyLog.Key()] = &dailyLog
+	return dailyLog.Copy(), m.writeDailyLog(dailyLog.Key(), dailyLog)
 }
 
 func (m *LogService) AddNewLog(date time.Time, name string, category model.Category) (model.DailyLog, error) {
-	dateString := timeconv.TimeToDayString(date)
-	dailyLog, _ := m.cache[dateString]
-	dailyLog.Logs = append(dailyLog.Logs, model.NewLog(name, category))
-	m.cache[dateString] = dailyLog
-	return m.SaveLog(date)
+	return m.AddLog(date, model.NewLog(name, category))
+}
+
+// AddLog appends the log at the end of the day.
+func (m *LogService) AddLog(date time.Time, log model.Log) (model.DailyLog, error) {
+	return m.update(fmt.Sprintf("add %q", log.Name), date, func(dailyLog *model.DailyLog) error {
+		dailyLog.Logs = append(dailyLog.Logs, log)
+		return nil
+	})
 }
 
 func (m *LogService) AppendNewLog(uuid string, date time.Time, name string, category model.Category) (model.DailyLog, error) {
-	dateString := timeconv.TimeToDayString(date)
-	dailyLog, _ := m.cache[dateString]
-	for index, appendLog := range dailyLog.Logs {
-		if appendLog.Id == uuid {
-			dailyLog.Logs[index].AppendNewSubLog(name, category)
+	return m.AppendLog(uuid, date, model.NewLog(name, category))
+}
+
+// AppendLog adds the log as a sub log of the log with the given id.
+func (m *LogService) AppendLog(uuid string, date time.Time, log model.Log) (model.DailyLog, error) {
+	return m.update(fmt.Sprintf("add %q", log.Name), date, func(dailyLog *model.DailyLog) error {
+		parent := dailyLog.Find(uuid)
+		if parent == nil {
+			return fmt.Errorf("%w: %v", ErrLogNotFound, uuid)
 		}
-	}
-	m.cache[dateString] = dailyLog
-	return m.SaveLog(date)
+		parent.AppendSubLog(log)
+		return nil
+	})
 }
 
 func (m *LogService) MoveExistingLog(date time.Time, previousLog model.Log) (model.DailyLog, error) {
-	dateString := timeconv.TimeToDayString(date)
-	dailyLog, _ := m.cache[dateString]
-	if previousLog.IsComplete() || previousLog.IsMigrated() || previousLog.IsIrrelevant() {
-		if previousLog.SubLogs != nil {
-			for _, item := range *previousLog.SubLogs {
-				if item.IsATask() {
-					dailyLog.Logs = append(dailyLog.Logs, item)
-				}
-			}
+	// The moved log is a new entry of the day, with its own ids.
+	previousLog = previousLog.Clone()
+	previousLog.RenewIds()
+	return m.update(fmt.Sprintf("move %q", previousLog.Name), date, func(dailyLog *model.DailyLog) error {
+		moveLog(dailyLog, previousLog)
+		return nil
+	})
+}
+
+// moveLog adds the copy of a log to the day, keeping only the pending tasks of
+// its whole tree, and returns the logs added.
+func moveLog(dailyLog *model.DailyLog, previousLog model.Log) []model.Log {
+	added := len(dailyLog.Logs)
+	dailyLog.Logs = append(dailyLog.Logs, pendingLogs(previousLog, true)...)
+	return dailyLog.Logs[added:]
+}
+
+// pendingLogs returns the log with only its pending sub logs. A log that is
+// not pending is dropped and its pending sub logs take its place. The logs of
+// the day are pending unless they are closed, the sub logs only when they are
+// tasks.
+func pendingLogs(log model.Log, top bool) []model.Log {
+	var subLogs []model.Log
+	if log.SubLogs != nil {
+		for _, subLog := range *log.SubLogs {
+			subLogs = append(subLogs, pendingLogs(subLog, false)...)
 		}
-	} else {
-		// Only keep the task logs.
-		if previousLog.SubLogs != nil {
-			var notDeletedSubLogs []model.Log
-			for _, item := range *previousLog.SubLogs {
-				if item.IsATask() {
-					notDeletedSubLogs = append(notDeletedSubLogs, item)
-				}
+	}
+	closed := log.IsComplete() || log.IsMigrated() || log.IsIrrelevant() || log.IsScheduled()
+	if !log.IsATask() && (!top || closed) {
+		return subLogs
+	}
+	log.SubLogs = nil
+	if len(subLogs) > 0 {
+		log.SubLogs = &subLogs
+	}
+	return []model.Log{log}
+}
+
+// logFile is a journal file whose logs can be found by id.
+type logFile interface {
+	Key() string
+	Find(id string) *model.Log
+}
+
+// linkMigrations points the logs of the file to the copies that were migrated
+// to the day with the key.
+func linkMigrations(from logFile, to string, copies []model.Log) {
+	for _, copied := range copies {
+		if copied.MigratedFrom != nil && copied.MigratedFrom.Date == from.Key() {
+			if original := from.Find(copied.MigratedFrom.Id); original != nil {
+				original.MigratedTo = &model.LogLink{Date: to, Id: copied.Id}
 			}
-			previousLog.SubLogs = &notDeletedSubLogs
 		}
-		dailyLog.Logs = append(dailyLog.Logs, previousLog)
+		if copied.SubLogs != nil {
+			linkMigrations(from, to, *copied.SubLogs)
+		}
+	}
+}
+
+// MigrateLog moves the log with the given id to the date and marks the
+// original as migrated.
+func (m *LogService) MigrateLog(id string, to time.Time) (model.DailyLog, error) {
+	defer m.command("migrate entry")()
+	return m.migrateLogLocked(id, to)
+}
+
+// migrateLogLocked is MigrateLog when the lock is already held.
+func (m *LogService) migrateLogLocked(id string, to time.Time) (model.DailyLog, error) {
+	from, log, err := m.findLog(id)
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	if from.Key() == timeconv.TimeToDayString(to) {
+		return from.Copy(), nil
 	}
+	var moved model.DailyLog
+	moveLog(&moved, log.MigrationCopy(from.Key()))
+	_, err = m.updateLocked(from.Date, func(dailyLog *model.DailyLog) error {
+		dailyLog.Find(id).MarkAsMigrated()
+		linkMigrations(dailyLog, timeconv.TimeToDayString(to), moved.Logs)
+		return nil
+	})
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	return m.updateLocked(to, func(dailyLog *model.DailyLog) error {
+		dailyLog.Logs = append(dailyLog.Logs, moved.Logs...)
+		return nil
+	})
+}
 
-	m.cache[dateString] = dailyLog
-	return m.SaveLog(date)
+// MigrateDay moves every log of the day to the date and marks the originals as
+// migrated.
+func (m *LogService) MigrateDay(from, to time.Time) (model.DailyLog, error) {
+	defer m.command("migrate " + timeconv.TimeToDayString(from))()
+	if timeconv.TimeToDayString(from) == timeconv.TimeToDayString(to) {
+		return model.DailyLog{}, errors.New("can't migrate a day to itself")
+	}
+	previous, err := m.day(from)
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	var moved model.DailyLog
+	for _, log := range previous.Logs {
+		moveLog(&moved, log.MigrationCopy(previous.Key()))
+	}
+	_, err = m.updateLocked(from, func(dailyLog *model.DailyLog) error {
+		for i := range dailyLog.Logs {
+			dailyLog.Logs[i].MarkAsMigrated()
+		}
+		linkMigrations(dailyLog, timeconv.TimeToDayString(to), moved.Logs)
+		return nil
+	})
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	return m.updateLocked(to, func(dailyLog *model.DailyLog) error {
+		dailyLog.Logs = append(dailyLog.Logs, moved.Logs...)
+		return nil
+	})
 }
 
+// SaveDay replaces the logs of the day.
+func (m *LogService) SaveDay(date time.Time, logs []model.Log) (model.DailyLog, error) {
+	return m.update("save "+timeconv.TimeToDayString(date), date, func(dailyLog *model.DailyLog) error {
+		dailyLog.SetLogs(logs)
+		return nil
+	})
+}
+
+// FindLog looks for the log with the given id in every day of the journal.
+func (m *LogService) FindLog(id string) (time.Time, model.Log, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	dailyLog, log, err := m.findLog(id)
+	if err != nil {
+		return time.Time{}, model.Log{}, err
+	}
+	return dailyLog.Date, log.Clone(), nil
+}
+
+// findLog returns the cached day and the log with the given id. The lock must
+// be held.
+func (m *LogService) findLog(id string) (*model.DailyLog, model.Log, error) {
+	// The cache has the days with changes that couldn't be saved yet.
+	for _, dailyLog := range m.cache {
+		if log := dailyLog.Find(id); log != nil {
+			return dailyLog, *log, nil
+		}
+	}
+	date, ok, err := m.indexedDate(id)
+	if err != nil {
+		return nil, model.Log{}, err
+	}
+	if ok {
+		dailyLog, err := m.day(date)
+		if err != nil {
+			return nil, model.Log{}, err
+		}
+		if log := dailyLog.Find(id); log != nil {
+			return dailyLog, *log, nil
+		}
+	}
+	return nil, model.Log{}, fmt.Errorf("%w: %v", ErrLogNotFound, id)
+}
+
+// UpdateLog applies the update to the log with the given id and saves its day.
+func (m *LogService) UpdateLog(id string, update func(log *model.Log) error) (time.Time, model.Log, error) {
+	return m.updateLog("update entry", id, update)
+}
+
+// updateLog is UpdateLog as the command with the name.
+func (m *LogService) updateLog(name, id string, update func(log *model.Log) error) (time.Time, model.Log, error) {
+	defer m.command(name)()
+	return m.updateLogLocked(id, update)
+}
+
+// updateLogLocked is UpdateLog when the lock is already held.
+func (m *LogService) updateLogLocked(id string, update func(log *model.Log) error) (time.Time, model.Log, error) {
+	from, _, err := m.findLog(id)
+	if err != nil {
+		return time.Time{}, model.Log{}, err
+	}
+	var updated model.Log
+	_, err = m.updateLocked(from.Date, func(dailyLog *model.DailyLog) error {
+		log := dailyLog.Find(id)
+		if err := update(log); err != nil {
+			return err
+		}
+		updated = log.Clone()
+		return nil
+	})
+	return from.Date, updated, err
+}
+
+// RenameLog changes the name of the log and when it is due or happens, a nil
+// moment clears it.
+func (m *LogService) RenameLog(id, name string, moment *model.Moment) (model.Log, error) {
+	_, log, err := m.updateLog("rename entry", id, func(log *model.Log) error {
+		log.Name = name
+		log.SetMoment(moment)
+		return nil
+	})
+	return log, err
+}
+
+// MarkLog applies one of the task marks to the log.
+func (m *LogService) MarkLog(id string, mark model.Category) (model.Log, error) {
+	_, log, err := m.updateLog(fmt.Sprintf("mark as %v", mark), id, markAs(mark))
+	return log, err
+}
+
+// markAs returns the update that applies the mark to a log.
+func markAs(mark model.Category) func(log *model.Log) error {
+	return func(log *model.Log) error {
+		if !log.MarkAs(mark) {
+			return fmt.Errorf("%w: %v can't be marked as %v", ErrInvalidMark, log.Mark, mark)
+		}
+		return nil
+	}
+}
+
+// SetImportant sets the priority of the log.
+func (m *LogService) SetImportant(id string, important bool) (model.Log, error) {
+	_, log, err := m.updateLog("set important", id, func(log *model.Log) error {
+		log.Important = important
+		return nil
+	})
+	return log, err
+}
+
+// ToggleImportant sets the priority of the log when it was not important, or
+// removes it otherwise, and returns the log with the date of its day.
+func (m *LogService) ToggleImportant(id string) (time.Time, model.Log, error) {
+	return m.updateLog("toggle important", id, func(log *model.Log) error {
+		log.Important = !log.Important
+		return nil
+	})
+}
+
+// DeleteLog removes the log with the given id and its sub logs.
+func (m *LogService) DeleteLog(id string) error {
+	defer m.command("delete entry")()
+	from, _, err := m.findLog(id)
+	if err != nil {
+		return err
+	}
+	_, err = m.updateLocked(from.Date, func(dailyLog *model.DailyLog) error {
+		dailyLog.Remove(id)
+		return nil
+	})
+	return err
+}
+
+// MoveLog moves the log the offset positions among its siblings, up when it is
+// negative.
+func (m *LogService) MoveLog(id string, offset int) (model.DailyLog, error) {
+	defer m.command("move entry")()
+	return m.rearrangeLocked(id, func(dailyLog *model.DailyLog) bool {
+		return dailyLog.Move(id, offset)
+	})
+}
+
+// IndentLog makes the log a sub log of the previous one.
+func (m *LogService) IndentLog(id string) (model.DailyLog, error) {
+	defer m.command("indent entry")()
+	return m.rearrangeLocked(id, func(dailyLog *model.DailyLog) bool {
+		return dailyLog.Indent(id)
+	})
+}
+
+// OutdentLog moves the sub log after its parent.
+func (m *LogService) OutdentLog(id string) (model.DailyLog, error) {
+	defer m.command("outdent entry")()
+	return m.rearrangeLocked(id, func(dailyLog *model.DailyLog) bool {
+		return dailyLog.Outdent(id)
+	})
+}
+
+// rearrangeLocked applies the change to the day of the log, failing with
+// ErrCantMove when it returns false. The lock must be held.
+func (m *LogService) rearrangeLocked(id string, change func(dailyLog *model.DailyLog) bool) (model.DailyLog, error) {
+	from, _, err := m.findLog(id)
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	return m.updateLocked(from.Date, func(dailyLog *model.DailyLog) error {
+		if !change(dailyLog) {
+			return fmt.Errorf("%w: %v", ErrCantMove, id)
+		}
+		return nil
+	})
+}
+
+// getPreviousFileName returns the most recent day with a file before the one
+// of from, using the journal index, or ErrNoPreviousDay. The lock must be held.
 func (m *LogService) getPreviousFileName(from time.Time) (time.Time, string, error) {
-	files, err := os.ReadDir(m.baseDir)
+	index, err := m.refreshJournalIndex()
 	if err != nil {
 		log.Print(err)
 		return time.Now(), "", err
 	}
-	startTime := time.Time{}
-	previousFileName := ""
-	actualDate := timeconv.TimeToDayString(from)
-	for _, file := range files {
-		if !file.IsDir() {
-			filename := file.Name()
-			extension := filepath.Ext(filename)
-			dateName := filename[0 : len(filename)-len(extension)]
-			if dateName == actualDate {
-				continue
-			}
-			toTime, err := timeconv.StringToDayTime(dateName)
-			if err != nil {
-				continue
-			}
-			if startTime.Before(toTime) {
-				startTime = toTime
-				previousFileName = dateName
-			}
-		}
+	// The indexed dates are days in UTC.
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	dates := index.sortedDates()
+	i := sort.Search(len(dates), func(i int) bool {
+		return !dates[i].Before(day)
+	})
+	if i == 0 {
+		return time.Time{}, "", ErrNoPreviousDay
 	}
-	return startTime, previousFileName, nil
+	return dates[i-1], timeconv.TimeToDayString(dates[i-1]), nil
 }
 
+// GetPreviousDate returns the most recent day with a file before the one of
+// from, or ErrNoPreviousDay.
 func (m *LogService) GetPreviousDate(from time.Time) (model.DailyLog, error) {
-	date, dateString, err := m.getPreviousFileName(from)
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	date, _, err := m.getPreviousFileName(from)
 	if err != nil {
 		return model.DailyLog{}, err
 	}
-	if val, ok := m.cache[dateString]; ok {
-		return val, nil
-	} else {
-		dailyLog, err := m.ReadDailyLog(date, dateString)
-		if err != nil {
-			return model.DailyLog{}, err
-		}
-		m.cache[dateString] = dailyLog
-		return dailyLog, nil
+	dailyLog, err := m.day(date)
+	if err != nil {
+		return model.DailyLog{}, err
 	}
+	return dailyLog.Copy(), nil
 }
 
+// SaveLog writes the cached day in its file.
 func (m *LogService) SaveLog(date time.Time) (model.DailyLog, error) {
-	dateString := timeconv.TimeToDayString(date)
-	dailyLog, _ := m.cache[dateString]
-	filePath := path.Join(m.baseDir, fmt.Sprintf("%v.yaml", dateString))
-	bytes, err := dailyLog.ToBytes()
+	defer m.command("save " + timeconv.TimeToDayString(date))()
+	dailyLog, err := m.day(date)
 	if err != nil {
-		return dailyLog, err
+		return model.DailyLog{}, err
+	}
+	return dailyLog.Copy(), m.writeDailyLog(dailyLog.Key(), *dailyLog)
+}
+
+// writeDailyLog saves the day in its file. The lock must be held.
+func (m *LogService) writeDailyLog(dateString string, dailyLog model.DailyLog) error {
+	bytes, err := dailyLog.ToBytes()
+	if err == nil {
+		err = m.writeFile(m.dayPath(dateString), bytes)
 	}
-	err = os.WriteFile(filePath, bytes, 0666)
 	if err != nil {
-		return dailyLog, err
+		m.dirty[dateString] = true
+		return err
 	}
-	return dailyLog, nil
+	delete(m.dirty, dateString)
+	m.rememberStamp(dateString)
+	m.forgetIndexedDay(dateString)
+	return nil
+}
+
+// GetIndex returns a copy of the index.
+func (m *LogService) GetIndex() model.Index {
+	m.mx.RLock()
+	defer m.mx.RUnlock()
+	items := make([]model.IndexItem, len(m.index.Items))
+	copy(items, m.index.Items)
+	return model.Index{Items: items}
 }
 
 func (m *LogService) SaveIndex() {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.saveIndex()
+}
+
+func (m *LogService) saveIndex() {
 	indexPath := path.Join(m.baseDir, indexFile)
-	bytes, err := m.Index.ToBytes()
+	bytes, err := m.index.ToBytes()
 	if err != nil {
 		log.Print("Error converting the index log", err, indexPath)
 		return
 	}
-	err = os.WriteFile(indexPath, bytes, 0666)
+	err = m.writeFile(indexPath, bytes)
 	if err != nil {
 		log.Print("Error saving the index log", err, indexPath)
 		return
@@ -198,8 +613,10 @@ func (m *LogService) SaveIndex() {
 }
 
 func (m *LogService) OpenIndexItem(index model.IndexItem) {
-	// TODO: Move the editor to a config file
-	err := utils.RunEditor("nvim", index.FullUrl)
+	m.mx.RLock()
+	editor := m.editor
+	m.mx.RUnlock()
+	err := utils.RunEditor(editor, index.FullUrl)
 	if err != nil {
 		log.Print("Error opening the editor", err, index.FullUrl)
 	}
@@ -216,8 +633,10 @@ func (m *LogService) CreateIndexItem(name string) {
 		log.Print("Error creating the file", err, indexItem.FullUrl)
 		return
 	}
-	m.Index.Items = append(m.Index.Items, indexItem)
-	m.SaveIndex()
+	m.mx.Lock()
+	m.index.Items = append(m.index.Items, indexItem)
+	m.saveIndex()
+	m.mx.Unlock()
 	m.OpenIndexItem(indexItem)
 }
 
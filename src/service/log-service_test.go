@@ -8,23 +8,25 @@ import (
 	"testing"
 	"time"
 
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDayToString(t *testing.T) {
 	date := time.Date(2002, 1, 1, 23, 59, 59, 0, time.UTC)
-	dateString := timeToString(date)
+	dateString := timeconv.TimeToDayString(date)
 	assert.Equal(t, "01.01.2002", dateString)
 }
 
 func TestStringToDate(t *testing.T) {
-	stringFromTimeValue, err := stringToTime("19.02.2022")
+	stringFromTimeValue, err := timeconv.StringToDayTime("19.02.2022")
 	assert.Nil(t, err)
 	assert.Equal(t, 19, stringFromTimeValue.Day())
 	assert.Equal(t, time.February, stringFromTimeValue.Month())
 	assert.Equal(t, 2022, stringFromTimeValue.Year())
 
-	stringFromTimeValue, err = stringToTime("index")
+	stringFromTimeValue, err = timeconv.StringToDayTime("index")
 	assert.Equal(t, 1, stringFromTimeValue.Day())
 	assert.Equal(t, time.January, stringFromTimeValue.Month())
 	assert.Equal(t, 1, stringFromTimeValue.Year())
@@ -49,25 +51,198 @@ func TestLoadPreviousDay(t *testing.T) {
 	dir, err := os.MkdirTemp("", "load_previous_day")
 	assert.Nil(t, err)
 
-	todayPath := path.Join(dir, fmt.Sprintf("%v.yaml", timeToString(time.Now())))
+	todayPath := path.Join(dir, fmt.Sprintf("%v.yaml", timeconv.TimeToDayString(time.Now())))
 	_, err = os.Create(todayPath)
 	assert.Nil(t, err)
 
-	yesterdayPath := path.Join(dir, fmt.Sprintf("%v.yaml", timeToString(time.Now().Add(-24*time.Hour))))
+	yesterdayPath := path.Join(dir, fmt.Sprintf("%v.yaml", timeconv.TimeToDayString(time.Now().Add(-24*time.Hour))))
 	_, err = os.Create(yesterdayPath)
 	assert.Nil(t, err)
 
 	specificDayDate := time.Date(2002, time.August, 22, 2, 20, 20, 20, time.UTC)
-	specificDayPath := path.Join(dir, fmt.Sprintf("%v.yaml", timeToString(specificDayDate)))
+	specificDayPath := path.Join(dir, fmt.Sprintf("%v.yaml", timeconv.TimeToDayString(specificDayDate)))
 	_, err = os.Create(specificDayPath)
 	assert.Nil(t, err)
 
 	logService := NewLogService(dir)
 
 	_, name, err := logService.getPreviousFileName(time.Now())
-	assert.Equal(t, timeToString(time.Now().Add(-24*time.Hour)), name)
+	assert.Equal(t, timeconv.TimeToDayString(time.Now().Add(-24*time.Hour)), name)
 	assert.Nil(t, err)
 
 	err = os.RemoveAll(dir)
 	assert.Nil(t, err)
 }
+
+func TestPreviousDateIsBeforeThePastDate(t *testing.T) {
+	dir := t.TempDir()
+	logService := NewLogService(dir)
+	august := time.Date(2026, time.August, 20, 0, 0, 0, 0, time.Local)
+	today := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.Local)
+	for _, date := range []time.Time{august, today} {
+		_, err := logService.AddNewLog(date, "Call the bank", model.Task)
+		assert.Nil(t, err)
+	}
+
+	// The days after the opened one are not previous days.
+	previous, err := logService.GetPreviousDate(time.Date(2026, time.September, 1, 0, 0, 0, 0, time.Local))
+	assert.Nil(t, err)
+	assert.Equal(t, "20.08.2026", previous.Key())
+	_, err = logService.GetPreviousDate(august)
+	assert.ErrorIs(t, err, ErrNoPreviousDay)
+	_, err = logService.GetPreviousDate(time.Date(2026, time.July, 1, 0, 0, 0, 0, time.Local))
+	assert.ErrorIs(t, err, ErrNoPreviousDay)
+}
+
+func TestIdsArePersisted(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Date(2022, time.February, 19, 0, 0, 0, 0, time.UTC)
+	dayPath := path.Join(dir, fmt.Sprintf("%v.yaml", timeconv.TimeToDayString(date)))
+	legacy := "items:\n  - name: parent\n    mark: task\n    subLogs:\n      - name: child\n        mark: note\n"
+	assert.Nil(t, os.WriteFile(dayPath, []byte(legacy), 0666))
+
+	dailyLog, err := NewLogService(dir).ReadDay(date)
+	assert.Nil(t, err)
+	parentId := dailyLog.Logs[0].Id
+	childId := (*dailyLog.Logs[0].SubLogs)[0].Id
+	assert.NotEmpty(t, parentId)
+	assert.NotEmpty(t, childId)
+
+	logService := NewLogService(dir)
+	dailyLog, err = logService.ReadDay(date)
+	assert.Nil(t, err)
+	assert.Equal(t, parentId, dailyLog.Logs[0].Id)
+	assert.Equal(t, childId, (*dailyLog.Logs[0].SubLogs)[0].Id)
+
+	dailyLog, err = logService.AppendNewLog(childId, date, "grandchild", model.Task)
+	assert.Nil(t, err)
+	assert.Equal(t, "grandchild", (*dailyLog.Find(childId).SubLogs)[0].Name)
+
+	_, err = logService.AppendNewLog("unknown", date, "orphan", model.Task)
+	assert.ErrorIs(t, err, ErrLogNotFound)
+}
+
+func TestReadDayReturnsACopy(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	date := time.Date(2022, time.February, 19, 0, 0, 0, 0, time.UTC)
+	name, moment, err := model.ParseMomentText("Report #work ^2022-02-21", date)
+	assert.Nil(t, err)
+	log := model.NewLog(name, model.Task)
+	log.SetMoment(moment)
+	_, err = logService.AddLog(date, log)
+	assert.Nil(t, err)
+
+	dailyLog, err := logService.ReadDay(date)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"work"}, dailyLog.Logs[0].Tags)
+	dailyLog.Logs[0].Due.Time = dailyLog.Logs[0].Due.Time.AddDate(1, 0, 0)
+	dailyLog.Logs[0].Tags[0] = "home"
+
+	dailyLog, err = logService.ReadDay(date)
+	assert.Nil(t, err)
+	assert.Equal(t, "2022-02-21", dailyLog.Logs[0].Due.String())
+	assert.Equal(t, []string{"work"}, dailyLog.Logs[0].Tags)
+}
+
+func TestRearrangeLogs(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	var ids []string
+	for _, name := range []string{"Bike", "Tube", "Pump"} {
+		dailyLog, err := logService.AddNewLog(date, name, model.Task)
+		assert.Nil(t, err)
+		ids = append(ids, dailyLog.Logs[len(dailyLog.Logs)-1].Id)
+	}
+	bike, tube, pump := ids[0], ids[1], ids[2]
+	names := func(logs []model.Log) []string {
+		var names []string
+		for _, log := range logs {
+			names = append(names, log.Name)
+		}
+		return names
+	}
+
+	dailyLog, err := logService.MoveLog(pump, -2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Pump", "Bike", "Tube"}, names(dailyLog.Logs))
+	_, err = logService.MoveLog(pump, -1)
+	assert.ErrorIs(t, err, ErrCantMove)
+	dailyLog, err = logService.MoveLog(pump, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bike", "Tube", "Pump"}, names(dailyLog.Logs))
+
+	_, err = logService.IndentLog(bike)
+	assert.ErrorIs(t, err, ErrCantMove)
+	_, err = logService.IndentLog(tube)
+	assert.Nil(t, err)
+	dailyLog, err = logService.IndentLog(pump)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bike"}, names(dailyLog.Logs))
+	assert.Equal(t, []string{"Tube", "Pump"}, names(*dailyLog.Logs[0].SubLogs))
+	assert.Equal(t, bike, (*dailyLog.Logs[0].SubLogs)[1].Parent.Id)
+
+	dailyLog, err = logService.MoveLog(pump, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Pump", "Tube"}, names(*dailyLog.Logs[0].SubLogs))
+
+	_, err = logService.OutdentLog(pump)
+	assert.Nil(t, err)
+	dailyLog, err = logService.OutdentLog(tube)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bike", "Tube", "Pump"}, names(dailyLog.Logs))
+	assert.Nil(t, dailyLog.Logs[0].SubLogs)
+	assert.Nil(t, dailyLog.Logs[1].Parent)
+	_, err = logService.OutdentLog(tube)
+	assert.ErrorIs(t, err, ErrCantMove)
+
+	// The changes are stored.
+	dailyLog, err = NewLogService(logService.baseDir).ReadDay(date)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bike", "Tube", "Pump"}, names(dailyLog.Logs))
+}
+
+func TestMigrateNestedLogs(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	from := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	// Trip (note) > Bike (done) > Tube (task) > Valve (task)
+	//                           > Spokes (note)
+	//             > Pump (task)
+	trip := model.NewLog("Trip", model.Note)
+	bike := model.NewLog("Bike", model.Complete)
+	tube := model.NewLog("Tube", model.Task)
+	tube.AppendNewSubLog("Valve", model.Task)
+	bike.AppendSubLog(tube)
+	bike.AppendNewSubLog("Spokes", model.Note)
+	trip.AppendSubLog(bike)
+	trip.AppendNewSubLog("Pump", model.Task)
+	_, err := logService.AddLog(from, trip)
+	assert.Nil(t, err)
+
+	// The whole tree is linked to the parents.
+	dailyLog, err := logService.ReadDay(from)
+	assert.Nil(t, err)
+	valve := (*(*(*dailyLog.Logs[0].SubLogs)[0].SubLogs)[0].SubLogs)[0]
+	assert.Equal(t, "Tube", valve.Parent.Name)
+	assert.Equal(t, "Bike", valve.Parent.Parent.Name)
+
+	// The pending tasks of the closed log take its place.
+	moved, err := logService.MigrateDay(from, to)
+	assert.Nil(t, err)
+	assert.Len(t, moved.Logs, 1)
+	assert.Equal(t, "Trip", moved.Logs[0].Name)
+	subLogs := *moved.Logs[0].SubLogs
+	assert.Equal(t, []string{"Tube", "Pump"}, []string{subLogs[0].Name, subLogs[1].Name})
+	assert.Equal(t, "Valve", (*subLogs[0].SubLogs)[0].Name)
+	assert.Equal(t, 3, moved.Logs[0].CountSubLogs())
+
+	// Every task of the tree is marked as migrated.
+	dailyLog, err = logService.ReadDay(from)
+	assert.Nil(t, err)
+	bikeLog := (*dailyLog.Logs[0].SubLogs)[0]
+	assert.True(t, bikeLog.IsComplete())
+	assert.True(t, (*bikeLog.SubLogs)[0].IsMigrated())
+	assert.True(t, (*(*bikeLog.SubLogs)[0].SubLogs)[0].IsMigrated())
+	assert.True(t, (*dailyLog.Logs[0].SubLogs)[1].IsMigrated())
+}
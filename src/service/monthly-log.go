@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+)
+
+func (m *LogService) monthPath(monthString string) string {
+	return path.Join(m.baseDir, fmt.Sprintf("%v.yaml", monthString))
+}
+
+// ReadMonth returns the monthly log of the month of the date.
+func (m *LogService) ReadMonth(date time.Time) (model.MonthlyLog, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	monthlyLog, err := m.month(date)
+	if err != nil {
+		return model.MonthlyLog{}, err
+	}
+	return monthlyLog.Copy(), nil
+}
+
+// month returns the cached month, reading it when needed. The lock must be
+// held.
+func (m *LogService) month(date time.Time) (*model.MonthlyLog, error) {
+	monthString := timeconv.TimeToMonthString(date)
+	if monthlyLog, ok := m.months[monthString]; ok {
+		return monthlyLog, nil
+	}
+	monthlyLog := model.NewMonthlyLog(date)
+	data, err := os.ReadFile(m.monthPath(monthString))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		monthlyLog, err = model.MonthlyFrom(data, date)
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.months[monthString] = &monthlyLog
+	return &monthlyLog, nil
+}
+
+// updateMonth applies the changes to a copy of the month, and when they
+// succeed stores the copy in the cache and in its file, as the command with
+// the name.
+func (m *LogService) updateMonth(name string, date time.Time, changes func(monthlyLog *model.MonthlyLog) error) (model.MonthlyLog, error) {
+	defer m.command(name)()
+	return m.updateMonthLocked(date, changes)
+}
+
+// updateMonthLocked is updateMonth when the lock is already held.
+func (m *LogService) updateMonthLocked(date time.Time, changes func(monthlyLog *model.MonthlyLog) error) (model.MonthlyLog, error) {
+	cached, err := m.month(date)
+	if err != nil {
+		return model.MonthlyLog{}, err
+	}
+	monthlyLog := cached.Copy()
+	if err := changes(&monthlyLog); err != nil {
+		return cached.Copy(), err
+	}
+	bytes, err := monthlyLog.ToBytes()
+	if err != nil {
+		return cached.Copy(), err
+	}
+	if err := m.writeFile(m.monthPath(monthlyLog.Key()), bytes); err != nil {
+		return cached.Copy(), err
+	}
+	m.months[monthlyLog.Key()] = &monthlyLog
+	return monthlyLog.Copy(), nil
+}
+
+// SaveMonth replaces the monthly log of its month.
+func (m *LogService) SaveMonth(monthlyLog model.MonthlyLog) (model.MonthlyLog, error) {
+	return m.updateMonth("save "+monthlyLog.Key(), monthlyLog.Date, func(cached *model.MonthlyLog) error {
+		*cached = monthlyLog.Copy()
+		return nil
+	})
+}
+
+// AddMonthlyLog adds the log to the task list of the month.
+func (m *LogService) AddMonthlyLog(date time.Time, log model.Log) (model.MonthlyLog, error) {
+	return m.updateMonth(fmt.Sprintf("add %q", log.Name), date, func(monthlyLog *model.MonthlyLog) error {
+		monthlyLog.Logs = append(monthlyLog.Logs, log)
+		return nil
+	})
+}
+
+// AddMonthlyEvent adds the log to the calendar of the month in the day of the
+// date.
+func (m *LogService) AddMonthlyEvent(date time.Time, log model.Log) (model.MonthlyLog, error) {
+	return m.updateMonth(fmt.Sprintf("add %q", log.Name), date, func(monthlyLog *model.MonthlyLog) error {
+		monthlyLog.AddDayLog(date.Day(), log)
+		return nil
+	})
+}
+
+// MarkMonthlyLog applies one of the task marks to a log of the month.
+func (m *LogService) MarkMonthlyLog(date time.Time, id string, mark model.Category) (model.MonthlyLog, error) {
+	return m.updateMonth(fmt.Sprintf("mark as %v", mark), date, func(monthlyLog *model.MonthlyLog) error {
+		log := monthlyLog.Find(id)
+		if log == nil {
+			return fmt.Errorf("%w: %v", ErrLogNotFound, id)
+		}
+		if !log.MarkAs(mark) {
+			return fmt.Errorf("%w: %v can't be marked as %v", ErrInvalidMark, log.Mark, mark)
+		}
+		return nil
+	})
+}
+
+// MigrateMonthlyLog moves a task of the month to the day and marks the
+// original as migrated.
+func (m *LogService) MigrateMonthlyLog(month time.Time, id string, to time.Time) (model.DailyLog, error) {
+	defer m.command("migrate entry")()
+	var moved model.DailyLog
+	_, err := m.updateMonthLocked(month, func(monthlyLog *model.MonthlyLog) error {
+		log := monthlyLog.Find(id)
+		if log == nil {
+			return fmt.Errorf("%w: %v", ErrLogNotFound, id)
+		}
+		moveLog(&moved, log.MigrationCopy(monthlyLog.Key()))
+		log.MarkAsMigrated()
+		linkMigrations(monthlyLog, timeconv.TimeToDayString(to), moved.Logs)
+		return nil
+	})
+	if err != nil {
+		return model.DailyLog{}, err
+	}
+	return m.updateLocked(to, func(dailyLog *model.DailyLog) error {
+		dailyLog.Logs = append(dailyLog.Logs, moved.Logs...)
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonthlyLogIsPersisted(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+
+	event := model.NewLog("Conference", model.Event)
+	_, err := logService.AddMonthlyEvent(date, event)
+	assert.Nil(t, err)
+	task := model.NewLog("Renew passport", model.Task)
+	_, err = logService.AddMonthlyLog(date, task)
+	assert.Nil(t, err)
+	_, err = os.Stat(path.Join(logService.baseDir, "10.2026.yaml"))
+	assert.Nil(t, err)
+
+	monthlyLog, err := NewLogService(logService.baseDir).ReadMonth(date)
+	assert.Nil(t, err)
+	assert.Equal(t, event.Id, monthlyLog.DayLogs(18)[0].Id)
+	assert.Equal(t, task.Id, monthlyLog.Logs[0].Id)
+
+	_, err = logService.MarkMonthlyLog(date, event.Id, model.Complete)
+	assert.ErrorIs(t, err, ErrInvalidMark)
+
+	dailyLog, err := logService.MigrateMonthlyLog(date, task.Id, date)
+	assert.Nil(t, err)
+	assert.Equal(t, "Renew passport", dailyLog.Logs[0].Name)
+	assert.NotEqual(t, task.Id, dailyLog.Logs[0].Id)
+	assert.Equal(t, &model.LogLink{Date: monthlyLog.Key(), Id: task.Id}, dailyLog.Logs[0].MigratedFrom)
+	assert.Equal(t, 1, dailyLog.Logs[0].Migrations)
+	monthlyLog, err = logService.ReadMonth(date)
+	assert.Nil(t, err)
+	assert.True(t, monthlyLog.Logs[0].IsMigrated())
+	assert.Equal(t, &model.LogLink{Date: dailyLog.Key(), Id: dailyLog.Logs[0].Id}, monthlyLog.Logs[0].MigratedTo)
+}
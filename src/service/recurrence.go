@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	zerolog "github.com/rs/zerolog/log"
+)
+
+const recurrenceFile = "recurrence.yaml"
+
+// ErrRecurrenceNotFound is returned when there is no recurrence with the id.
+var ErrRecurrenceNotFound = errors.New("recurrence not found")
+
+func (m *LogService) recurrencePath() string {
+	return path.Join(m.baseDir, recurrenceFile)
+}
+
+// ReadRecurrences returns the recurrences of the journal.
+func (m *LogService) ReadRecurrences() (model.RecurrenceLog, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return m.recurrences()
+}
+
+// recurrences reads the recurrence file, it is not cached so the changes made
+// by hand are seen by the next day opened. The lock must be held.
+func (m *LogService) recurrences() (model.RecurrenceLog, error) {
+	data, err := os.ReadFile(m.recurrencePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return model.RecurrenceLog{Entries: []model.Recurrence{}}, nil
+	}
+	if err != nil {
+		return model.RecurrenceLog{}, err
+	}
+	recurrenceLog, err := model.RecurrencesFrom(data)
+	if err != nil {
+		return recurrenceLog, fmt.Errorf("parsing %v: %w", recurrenceFile, err)
+	}
+	if recurrenceLog.IdsFilled() {
+		// Keep the ids of the recurrences written by hand, the days remember
+		// the recurrences they got by id.
+		if err := m.writeRecurrences(recurrenceLog); err != nil {
+			zerolog.Print("Error saving the recurrence ids ", err)
+		}
+	}
+	return recurrenceLog, nil
+}
+
+func (m *LogService) writeRecurrences(recurrenceLog model.RecurrenceLog) error {
+	bytes, err := recurrenceLog.ToBytes()
+	if err != nil {
+		return err
+	}
+	return m.writeFile(m.recurrencePath(), bytes)
+}
+
+// isFuture checks if the day of the date is after today.
+func (m *LogService) isFuture(date time.Time) bool {
+	now := m.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, date.Location())
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return day.After(today)
+}
+
+// AddRecurrence stores the recurrence and adds its entry to the days already
+// read, up to today, that it happens on.
+func (m *LogService) AddRecurrence(recurrence model.Recurrence) (model.RecurrenceLog, error) {
+	if err := recurrence.Validate(); err != nil {
+		return model.RecurrenceLog{}, err
+	}
+	defer m.command(fmt.Sprintf("add recurrence %q", recurrence.Name))()
+	recurrenceLog, err := m.recurrences()
+	if err != nil {
+		return recurrenceLog, err
+	}
+	recurrenceLog.Entries = append(recurrenceLog.Entries, recurrence)
+	if err := m.writeRecurrences(recurrenceLog); err != nil {
+		return recurrenceLog, err
+	}
+	for dateString := range m.recurred {
+		date, err := timeconv.StringToDayTime(dateString)
+		if err != nil {
+			continue
+		}
+		if err := m.recurLocked(date, recurrenceLog); err != nil {
+			return recurrenceLog, err
+		}
+	}
+	return recurrenceLog, nil
+}
+
+// DeleteRecurrence removes the recurrence, the entries already added to the
+// days are kept.
+func (m *LogService) DeleteRecurrence(id string) (model.RecurrenceLog, error) {
+	defer m.command("delete recurrence")()
+	recurrenceLog, err := m.recurrences()
+	if err != nil {
+		return recurrenceLog, err
+	}
+	if !recurrenceLog.Remove(id) {
+		return recurrenceLog, fmt.Errorf("%w: %v", ErrRecurrenceNotFound, id)
+	}
+	return recurrenceLog, m.writeRecurrences(recurrenceLog)
+}
+
+// recurLocked adds to the day the entries of the recurrences that happen on
+// it and were not added yet. The lock must be held.
+func (m *LogService) recurLocked(date time.Time, recurrenceLog model.RecurrenceLog) error {
+	cached, err := m.day(date)
+	if err != nil {
+		return err
+	}
+	added := map[string]bool{}
+	for _, id := range cached.Recurrences {
+		added[id] = true
+	}
+	var due []model.Recurrence
+	for _, recurrence := range recurrenceLog.Entries {
+		if added[recurrence.Id] {
+			continue
+		}
+		occurs, err := recurrence.Occurs(date)
+		if err != nil {
+			zerolog.Print("Error in the recurrence ", recurrence.Name, " ", err)
+			continue
+		}
+		if occurs {
+			due = append(due, recurrence)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	_, err = m.updateLocked(date, func(dailyLog *model.DailyLog) error {
+		for _, recurrence := range due {
+			dailyLog.Logs = append(dailyLog.Logs, recurrence.Instance(date))
+			dailyLog.Recurrences = append(dailyLog.Recurrences, recurrence.Id)
+		}
+		return nil
+	})
+	return err
+}
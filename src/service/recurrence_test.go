@@ -0,0 +1,97 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrencesAreAddedOnce(t *testing.T) {
+	dir := t.TempDir()
+	// Monday.
+	monday := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local)
+	sunday := monday.AddDate(0, 0, -1)
+	tuesday := monday.AddDate(0, 0, 1)
+	newLogService := func() *LogService {
+		logService := NewLogService(dir)
+		logService.now = func() time.Time { return tuesday.Add(12 * time.Hour) }
+		return logService
+	}
+	logService := newLogService()
+	_, err := logService.ReadDay(monday)
+	assert.Nil(t, err)
+
+	// The days already opened get the new recurrence.
+	standup := model.NewRecurrence("weekdays", "Standup", model.Event, sunday)
+	standup.Time = "09:30"
+	_, err = logService.AddRecurrence(standup)
+	assert.Nil(t, err)
+	dailyLog, err := logService.ReadDay(monday)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+	assert.Equal(t, "Standup", dailyLog.Logs[0].Name)
+	assert.Equal(t, "2026-10-19 09:30", dailyLog.Logs[0].At.String())
+
+	// A recurrence written by hand gets an id that is kept.
+	data := "items:\n" +
+		"  - id: " + standup.Id + "\n    rule: weekdays\n    start: 2026-10-18\n    name: Standup\n    mark: event\n    time: \"09:30\"\n" +
+		"  - rule: FREQ=DAILY\n    start: 2026-10-01\n    name: Water the plants\n    mark: task\n"
+	assert.Nil(t, os.WriteFile(logService.recurrencePath(), []byte(data), 0644))
+
+	dailyLog, err = newLogService().ReadDay(tuesday)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 2)
+	assert.Len(t, dailyLog.Recurrences, 2)
+	recurrences, err := logService.ReadRecurrences()
+	assert.Nil(t, err)
+	assert.Equal(t, dailyLog.Recurrences[1], recurrences.Entries[1].Id)
+
+	// Reading again or deleting an instance doesn't add it again.
+	logService = newLogService()
+	assert.Nil(t, logService.DeleteLog(dailyLog.Logs[1].Id))
+	dailyLog, err = newLogService().ReadDay(tuesday)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+
+	dailyLog, err = newLogService().ReadDay(sunday)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+	assert.Equal(t, "Water the plants", dailyLog.Logs[0].Name)
+
+	_, err = logService.DeleteRecurrence(standup.Id)
+	assert.Nil(t, err)
+	_, err = logService.DeleteRecurrence(standup.Id)
+	assert.ErrorIs(t, err, ErrRecurrenceNotFound)
+}
+
+func TestRecurrencesAreNotAddedToTheFuture(t *testing.T) {
+	dir := t.TempDir()
+	logService := NewLogService(dir)
+	assert.Nil(t, logService.StartSession())
+	today := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.Local)
+	logService.now = func() time.Time { return today.Add(12 * time.Hour) }
+	_, err := logService.AddRecurrence(model.NewRecurrence("daily", "Water plants", model.Task, today))
+	assert.Nil(t, err)
+
+	// Reading a future day writes no file.
+	tomorrow := today.AddDate(0, 0, 1)
+	dailyLog, err := logService.ReadDay(tomorrow)
+	assert.Nil(t, err)
+	assert.Empty(t, dailyLog.Logs)
+	_, err = os.Stat(logService.dayPath(dailyLog.Key()))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Today gets the entry once, as a command that can be undone.
+	dailyLog, err = logService.ReadDay(today)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+	command, err := logService.Undo()
+	assert.Nil(t, err)
+	assert.Equal(t, "add recurring entries", command.Name)
+	dailyLog, err = logService.ReadDay(today)
+	assert.Nil(t, err)
+	assert.Empty(t, dailyLog.Logs)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+)
+
+// OpenTasks returns the tasks still open in the days before the date, at any
+// depth, the most recent days first.
+func (m *LogService) OpenTasks(before time.Time) ([]search.Result, error) {
+	dayBefore := before.AddDate(0, 0, -1)
+	return m.Search(search.Query{Marks: []model.Category{model.Task}, Before: &dayBefore})
+}
+
+// ImportantTasks returns the important tasks still open in every day of the
+// journal, at any depth, the most recent days first.
+func (m *LogService) ImportantTasks() ([]search.Result, error) {
+	important := true
+	return m.Search(search.Query{Marks: []model.Category{model.Task}, Important: &important})
+}
+
+// bulk applies the action to every log that is still a task, the logs that
+// stopped being tasks by a previous action, like the sub logs of a migrated
+// log, are skipped. It stops at the first error. The lock must be held.
+func (m *LogService) bulk(ids []string, action func(id string) error) error {
+	for _, id := range ids {
+		_, log, err := m.findLog(id)
+		if err != nil {
+			return err
+		}
+		if !log.IsATask() {
+			continue
+		}
+		if err := action(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateLogs migrates the tasks to the date.
+func (m *LogService) MigrateLogs(ids []string, to time.Time) error {
+	defer m.command(fmt.Sprintf("migrate %d entries", len(ids)))()
+	return m.bulk(ids, func(id string) error {
+		_, err := m.migrateLogLocked(id, to)
+		return err
+	})
+}
+
+// ScheduleLogs schedules the tasks in the future log, see ScheduleLog.
+func (m *LogService) ScheduleLogs(ids []string, date time.Time, wholeMonth bool) error {
+	defer m.command(fmt.Sprintf("schedule %d entries", len(ids)))()
+	return m.bulk(ids, func(id string) error {
+		_, err := m.scheduleLogLocked(id, date, wholeMonth)
+		return err
+	})
+}
+
+// MarkLogs applies one of the task marks to the tasks.
+func (m *LogService) MarkLogs(ids []string, mark model.Category) error {
+	defer m.command(fmt.Sprintf("mark %d entries as %v", len(ids), mark))()
+	return m.bulk(ids, func(id string) error {
+		_, _, err := m.updateLogLocked(id, markAs(mark))
+		return err
+	})
+}
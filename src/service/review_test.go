@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewOpenTasks(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	today := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	logService.now = func() time.Time { return today }
+	old := today.AddDate(0, 0, -40)
+	recent := today.AddDate(0, 0, -2)
+
+	dailyLog, err := logService.AddNewLog(old, "Fix the bike", model.Task)
+	assert.Nil(t, err)
+	bike := dailyLog.Logs[0].Id
+	dailyLog, err = logService.AppendNewLog(bike, old, "Buy a tube", model.Task)
+	assert.Nil(t, err)
+	tube := (*dailyLog.Logs[0].SubLogs)[0].Id
+	_, err = logService.AddNewLog(old, "Done already", model.Complete)
+	assert.Nil(t, err)
+	dailyLog, err = logService.AddNewLog(recent, "Call mum", model.Task)
+	assert.Nil(t, err)
+	mum := dailyLog.Logs[0].Id
+	dailyLog, err = logService.AddNewLog(recent, "Renew passport", model.Task)
+	assert.Nil(t, err)
+	passport := dailyLog.Logs[1].Id
+	_, err = logService.AddNewLog(today, "Not reviewed", model.Task)
+	assert.Nil(t, err)
+
+	results, err := logService.OpenTasks(today)
+	assert.Nil(t, err)
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Log.Name)
+	}
+	assert.Equal(t, []string{"Call mum", "Renew passport", "Fix the bike", "Buy a tube"}, names)
+
+	// The sub task is migrated with its parent, only once.
+	assert.Nil(t, logService.MigrateLogs([]string{bike, tube}, today))
+	dailyLog, err = logService.ReadDay(today)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 2)
+	assert.Equal(t, "Fix the bike", dailyLog.Logs[1].Name)
+	assert.Len(t, *dailyLog.Logs[1].SubLogs, 1)
+
+	assert.Nil(t, logService.MarkLogs([]string{mum}, model.Irrelevant))
+	assert.Nil(t, logService.ScheduleLogs([]string{passport}, today.AddDate(0, 2, 0), true))
+
+	results, err = logService.OpenTasks(today)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
+
+func TestImportantTasks(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	today := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+
+	dailyLog, err := logService.AddNewLog(today.AddDate(0, 0, -1), "Pay rent", model.Task)
+	assert.Nil(t, err)
+	rent := dailyLog.Logs[0].Id
+	dailyLog, err = logService.AddNewLog(today, "Call mum", model.Task)
+	assert.Nil(t, err)
+	mum := dailyLog.Logs[0].Id
+	dailyLog, err = logService.AddNewLog(today, "Birthday", model.Event)
+	assert.Nil(t, err)
+	birthday := dailyLog.Logs[1].Id
+
+	for _, id := range []string{rent, mum, birthday} {
+		_, log, err := logService.ToggleImportant(id)
+		assert.Nil(t, err)
+		assert.True(t, log.Important)
+	}
+	results, err := logService.ImportantTasks()
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Call mum", results[0].Log.Name)
+	assert.Equal(t, "Pay rent", results[1].Log.Name)
+
+	// Closed or not important tasks are left out.
+	_, log, err := logService.ToggleImportant(mum)
+	assert.Nil(t, err)
+	assert.False(t, log.Important)
+	_, err = logService.MarkLog(rent, model.Complete)
+	assert.Nil(t, err)
+	results, err = logService.ImportantTasks()
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
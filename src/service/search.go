@@ -0,0 +1,106 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+)
+
+// Search returns the logs of the whole journal that match the query, the most
+// recent days first. The journal index gives the days that can match, so only
+// those are read.
+func (m *LogService) Search(query search.Query) ([]search.Result, error) {
+	index, err := m.readJournalIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer m.mx.RUnlock()
+	return m.searchIndex(index, query), nil
+}
+
+// searchIndex returns the logs of the days of the index that match the query,
+// the most recent days first. The read lock must be held.
+func (m *LogService) searchIndex(index *journalIndex, query search.Query) []search.Result {
+	var results []search.Result
+	for _, dailyLog := range m.readIndexedDays(m.toDates(candidateDays(index, query))) {
+		results = append(results, query.Search(&dailyLog)...)
+	}
+	return results
+}
+
+// candidateDays returns the days of the index that can have logs matching the
+// query, the days out of its dates are left out.
+func candidateDays(index *journalIndex, query search.Query) map[string]bool {
+	var sets []map[string]bool
+	if len(query.Marks) > 0 {
+		marks := map[string]bool{}
+		for _, mark := range query.Marks {
+			marks[string(mark)] = true
+		}
+		sets = append(sets, index.matchingDays(keyMark, func(value string) bool {
+			return marks[value]
+		}))
+	}
+	if query.Important != nil {
+		sets = append(sets, toSet(index.days(keyImportant, strconv.FormatBool(*query.Important))))
+	}
+	for _, tag := range query.Tags {
+		sets = append(sets, toSet(index.days(keyTag, tag)))
+	}
+	for _, context := range query.Contexts {
+		sets = append(sets, toSet(index.days(keyContext, context)))
+	}
+	// The texts are found inside the words, every word of a text is part of a
+	// word of the matching logs.
+	for _, text := range query.Texts {
+		for _, token := range tokens(text) {
+			token := token
+			sets = append(sets, index.matchingDays(keyToken, func(value string) bool {
+				return strings.Contains(value, token)
+			}))
+		}
+	}
+
+	// Without keys every day can match, else the smallest set of days is
+	// checked against the others.
+	candidates, others := map[string]bool{}, sets
+	if len(sets) == 0 {
+		for dateString := range index.Days {
+			candidates[dateString] = true
+		}
+	} else {
+		sort.Slice(sets, func(i, j int) bool {
+			return len(sets[i]) < len(sets[j])
+		})
+		candidates, others = sets[0], sets[1:]
+	}
+	days := map[string]bool{}
+	for dateString := range candidates {
+		date, err := timeconv.StringToDayTime(dateString)
+		if err != nil || !query.MatchesDay(date) {
+			continue
+		}
+		found := true
+		for _, set := range others {
+			if !set[dateString] {
+				found = false
+				break
+			}
+		}
+		if found {
+			days[dateString] = true
+		}
+	}
+	return days
+}
+
+func toSet(counts map[string]int) map[string]bool {
+	set := make(map[string]bool, len(counts))
+	for dateString := range counts {
+		set[dateString] = true
+	}
+	return set
+}
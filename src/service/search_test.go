@@ -0,0 +1,48 @@
+package service
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchReadsEveryDayAndLinkedFiles(t *testing.T) {
+	dir := t.TempDir()
+	logService := NewLogService(dir)
+	first := time.Date(2026, time.September, 2, 0, 0, 0, 0, time.Local)
+	second := time.Date(2026, time.October, 5, 0, 0, 0, 0, time.Local)
+	_, err := logService.AddNewLog(first, "Send invoice", model.Task)
+	assert.Nil(t, err)
+	_, err = logService.AddNewLog(second, "Check payment", model.Task)
+	assert.Nil(t, err)
+
+	assert.Nil(t, os.WriteFile(path.Join(dir, "notes.md"), []byte("The invoice 42 is paid"), 0644))
+	url := "notes.md"
+	note := model.NewLog("Meeting notes", model.Note)
+	note.Url = &url
+	_, err = logService.AddLog(second, note)
+	assert.Nil(t, err)
+
+	query, err := search.Parse("invoice", time.Now())
+	assert.Nil(t, err)
+	// A new service has nothing cached.
+	results, err := NewLogService(dir).Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Meeting notes", results[0].Log.Name)
+	assert.Equal(t, "05.10.2026", timeconv.TimeToDayString(results[0].Date))
+	assert.Equal(t, "Send invoice", results[1].Log.Name)
+
+	query, err = search.Parse("mark:task after:2026-10-01", time.Now())
+	assert.Nil(t, err)
+	results, err = logService.Search(query)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Check payment", results[0].Log.Name)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
+)
+
+// Tags returns every #tag and @context of the days of the journal with the
+// number of logs that have it, sorted by name, the tags first.
+func (m *LogService) Tags() ([]model.TagCount, error) {
+	index, err := m.readJournalIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer m.mx.RUnlock()
+	counts := map[string]int{}
+	for kind, prefix := range map[string]rune{keyTag: model.TagPrefix, keyContext: model.ContextPrefix} {
+		for tag, days := range index.lookup[kind] {
+			for _, count := range days {
+				counts[string(prefix)+tag] += count
+			}
+		}
+	}
+	tags := make([]model.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, model.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
+
+// TaggedLogs returns the logs with the #tag or the @context, at any depth,
+// the most recent days first. A name without prefix is a tag.
+func (m *LogService) TaggedLogs(tag string) ([]search.Result, error) {
+	var query search.Query
+	if strings.HasPrefix(tag, string(model.ContextPrefix)) {
+		query.Contexts = []string{strings.ToLower(tag[1:])}
+	} else {
+		query.Tags = []string{strings.ToLower(strings.TrimPrefix(tag, string(model.TagPrefix)))}
+	}
+	return m.Search(query)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	dir := t.TempDir()
+	logService := NewLogService(dir)
+	first := time.Date(2026, time.October, 2, 0, 0, 0, 0, time.Local)
+	second := time.Date(2026, time.October, 5, 0, 0, 0, 0, time.Local)
+	dailyLog, err := logService.AddNewLog(first, "Send invoice #work @office", model.Task)
+	assert.Nil(t, err)
+	_, err = logService.AppendNewLog(dailyLog.Logs[0].Id, first, "Ask for the #Work number", model.Note)
+	assert.Nil(t, err)
+	dailyLog, err = logService.AddNewLog(second, "Fix the bike #home", model.Task)
+	assert.Nil(t, err)
+
+	// Renaming parses the tags again.
+	_, err = logService.RenameLog(dailyLog.Logs[0].Id, "Fix the bike #bike @home", nil)
+	assert.Nil(t, err)
+
+	tags, err := NewLogService(dir).Tags()
+	assert.Nil(t, err)
+	assert.Equal(t, []model.TagCount{{Tag: "#bike", Count: 1}, {Tag: "#work", Count: 2}, {Tag: "@home", Count: 1}, {Tag: "@office", Count: 1}}, tags)
+
+	results, err := logService.TaggedLogs("#work")
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Send invoice #work @office", results[0].Log.Name)
+	results, err = logService.TaggedLogs("@home")
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Fix the bike #bike @home", results[0].Log.Name)
+}
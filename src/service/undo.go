@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apoloa/bjournal/src/utils"
+	"github.com/google/uuid"
+	zerolog "github.com/rs/zerolog/log"
+)
+
+const (
+	undoFile = "undo.json"
+	// maxCommands is the number of commands that can be undone.
+	maxCommands = 100
+)
+
+var (
+	// ErrNothingToUndo is returned when there are no commands to undo.
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrNothingToRedo is returned when there are no undone commands to redo.
+	ErrNothingToRedo = errors.New("nothing to redo")
+	// ErrUndoConflict is returned when a file of the command changed after it,
+	// undoing or redoing it would lose those changes.
+	ErrUndoConflict = errors.New("the journal changed after the command")
+)
+
+// fileChange is a file written by a command, with its content before and
+// after it.
+type fileChange struct {
+	// Path is relative to the journal directory.
+	Path string `json:"path"`
+	// Before is nil when the file didn't exist.
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+}
+
+// Command is a change of the journal made by one call to the service, like
+// migrating a day, that can be undone and redone as a whole.
+type Command struct {
+	Name    string       `json:"name"`
+	Time    time.Time    `json:"time"`
+	Changes []fileChange `json:"changes"`
+}
+
+// undoHistory is the stack of commands of the session, stored in the
+// .bjournal directory so every process of the journal shares it.
+type undoHistory struct {
+	Session string    `json:"session"`
+	Done    []Command `json:"done"`
+	Undone  []Command `json:"undone"`
+}
+
+func (m *LogService) undoPath() string {
+	return path.Join(m.baseDir, metaDir, undoFile)
+}
+
+// loadUndoHistory reads the stored history, a history that can't be read is
+// empty. The lock must be held.
+func (m *LogService) loadUndoHistory() undoHistory {
+	var history undoHistory
+	data, err := os.ReadFile(m.undoPath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			zerolog.Print("Error reading the undo history ", err)
+		}
+		return history
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		zerolog.Print("Error reading the undo history ", err)
+		return undoHistory{}
+	}
+	return history
+}
+
+func (m *LogService) saveUndoHistory(history undoHistory) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(m.baseDir, metaDir), 0755); err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(m.undoPath(), data, 0644)
+}
+
+// StartSession starts a new undo history, the commands of the previous session
+// can't be undone anymore.
+func (m *LogService) StartSession() error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return m.saveUndoHistory(undoHistory{Session: uuid.NewString()})
+}
+
+// command takes the lock and records the files written until the returned
+// function is called, which stores them as a command of the undo history and
+// releases the lock.
+func (m *LogService) command(name string) func() {
+	m.mx.Lock()
+	m.recording = &Command{Name: name, Time: m.now()}
+	return func() {
+		defer m.mx.Unlock()
+		command := m.recording
+		m.recording = nil
+		if len(command.Changes) == 0 {
+			return
+		}
+		history := m.loadUndoHistory()
+		history.Done = append(history.Done, *command)
+		if len(history.Done) > maxCommands {
+			history.Done = history.Done[len(history.Done)-maxCommands:]
+		}
+		history.Undone = nil
+		if err := m.saveUndoHistory(history); err != nil {
+			zerolog.Print("Error saving the undo history ", err)
+		}
+	}
+}
+
+// recordWrite adds the file to the recorded command before it is written. The
+// lock must be held.
+func (m *LogService) recordWrite(filePath string, data []byte) {
+	if m.recording == nil {
+		return
+	}
+	relative, err := filepath.Rel(m.baseDir, filePath)
+	if err != nil {
+		return
+	}
+	after := string(data)
+	for i, change := range m.recording.Changes {
+		if change.Path == relative {
+			m.recording.Changes[i].After = &after
+			return
+		}
+	}
+	change := fileChange{Path: relative, After: &after}
+	if current, err := os.ReadFile(filePath); err == nil {
+		before := string(current)
+		change.Before = &before
+	}
+	m.recording.Changes = append(m.recording.Changes, change)
+}
+
+// Undo reverts the files of the last command to their content before it.
+func (m *LogService) Undo() (Command, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	history := m.loadUndoHistory()
+	if len(history.Done) == 0 {
+		return Command{}, ErrNothingToUndo
+	}
+	command := history.Done[len(history.Done)-1]
+	if err := m.applyChanges(command, true); err != nil {
+		return Command{}, err
+	}
+	history.Done = history.Done[:len(history.Done)-1]
+	history.Undone = append(history.Undone, command)
+	return command, m.saveUndoHistory(history)
+}
+
+// Redo applies again the last undone command.
+func (m *LogService) Redo() (Command, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	history := m.loadUndoHistory()
+	if len(history.Undone) == 0 {
+		return Command{}, ErrNothingToRedo
+	}
+	command := history.Undone[len(history.Undone)-1]
+	if err := m.applyChanges(command, false); err != nil {
+		return Command{}, err
+	}
+	history.Undone = history.Undone[:len(history.Undone)-1]
+	history.Done = append(history.Done, command)
+	return command, m.saveUndoHistory(history)
+}
+
+// applyChanges writes the content of the files before the command when undo
+// is true, or after it otherwise. Nothing is written when any of the files
+// changed since, and the files already written are restored when one of them
+// can't be written. The lock must be held.
+func (m *LogService) applyChanges(command Command, undo bool) error {
+	for _, change := range command.Changes {
+		expected := change.After
+		if !undo {
+			expected = change.Before
+		}
+		current, err := os.ReadFile(path.Join(m.baseDir, change.Path))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		exists := err == nil
+		if exists != (expected != nil) || (exists && !bytes.Equal(current, []byte(*expected))) {
+			return fmt.Errorf("%w: %v", ErrUndoConflict, change.Path)
+		}
+	}
+	for i, change := range command.Changes {
+		content := change.Before
+		if !undo {
+			content = change.After
+		}
+		filePath := path.Join(m.baseDir, change.Path)
+		var err error
+		if content == nil {
+			err = os.Remove(filePath)
+		} else {
+			err = m.writeFile(filePath, []byte(*content))
+		}
+		if err != nil {
+			m.rollbackChanges(command.Changes[:i], undo)
+			return err
+		}
+		m.forgetFile(change.Path)
+	}
+	return nil
+}
+
+// rollbackChanges restores the files written by applyChanges to the content
+// they had before. The lock must be held.
+func (m *LogService) rollbackChanges(changes []fileChange, undo bool) {
+	for _, change := range changes {
+		content := change.After
+		if !undo {
+			content = change.Before
+		}
+		filePath := path.Join(m.baseDir, change.Path)
+		var err error
+		if content == nil {
+			err = os.Remove(filePath)
+		} else {
+			err = utils.WriteFileAtomic(filePath, []byte(*content), 0644)
+		}
+		if err != nil {
+			zerolog.Print("Error restoring ", change.Path, " ", err)
+		}
+		m.forgetFile(change.Path)
+	}
+}
+
+// forgetFile drops the cached content of the journal file, so it is read again
+// when needed. The lock must be held.
+func (m *LogService) forgetFile(relative string) {
+	key := strings.TrimSuffix(filepath.Base(relative), ".yaml")
+	delete(m.cache, key)
+	delete(m.dirty, key)
+	delete(m.months, key)
+	delete(m.futures, key)
+	delete(m.stamps, key)
+	m.rememberStamp(key)
+	m.forgetIndexedDay(key)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUndoRedo(t *testing.T) {
+	baseDir := t.TempDir()
+	logService := NewLogService(baseDir)
+	assert.Nil(t, logService.StartSession())
+	yesterday := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+
+	_, err := logService.Undo()
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+
+	dailyLog, err := logService.AddNewLog(yesterday, "Fix the bike", model.Task)
+	assert.Nil(t, err)
+	bike := dailyLog.Logs[0].Id
+	_, err = logService.AddNewLog(yesterday, "Call mum", model.Task)
+	assert.Nil(t, err)
+
+	// Migrating the day changes two files in a single command.
+	_, err = logService.MigrateDay(yesterday, today)
+	assert.Nil(t, err)
+	command, err := logService.Undo()
+	assert.Nil(t, err)
+	assert.Equal(t, "migrate 17.10.2026", command.Name)
+	dailyLog, err = logService.ReadDay(yesterday)
+	assert.Nil(t, err)
+	assert.Equal(t, model.Task, dailyLog.Logs[0].Mark)
+	assert.Nil(t, dailyLog.Logs[0].MigratedTo)
+	_, err = os.Stat(logService.dayPath("18.10.2026"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	command, err = logService.Redo()
+	assert.Nil(t, err)
+	assert.Equal(t, "migrate 17.10.2026", command.Name)
+	dailyLog, err = logService.ReadDay(today)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 2)
+	_, err = logService.Redo()
+	assert.ErrorIs(t, err, ErrNothingToRedo)
+
+	// The history is shared with other services of the journal.
+	other := NewLogService(baseDir)
+	command, err = other.Undo()
+	assert.Nil(t, err)
+	assert.Equal(t, "migrate 17.10.2026", command.Name)
+	command, err = other.Undo()
+	assert.Nil(t, err)
+	assert.Equal(t, `add "Call mum"`, command.Name)
+	dailyLog, err = other.ReadDay(yesterday)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+
+	// A new command drops the undone ones.
+	_, err = logService.MarkLog(bike, model.Complete)
+	assert.Nil(t, err)
+	_, err = logService.Redo()
+	assert.ErrorIs(t, err, ErrNothingToRedo)
+
+	// Files changed outside of the history are not overwritten.
+	assert.Nil(t, os.WriteFile(logService.dayPath("17.10.2026"), []byte("logs: []\n"), 0644))
+	_, err = logService.Undo()
+	assert.ErrorIs(t, err, ErrUndoConflict)
+
+	assert.Nil(t, logService.StartSession())
+	_, err = logService.Undo()
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+}
+
+func TestUndoRestoresTheFilesOnError(t *testing.T) {
+	baseDir := t.TempDir()
+	logService := NewLogService(baseDir).SetBackups(1)
+	assert.Nil(t, logService.StartSession())
+	yesterday := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+	dailyLog, err := logService.AddNewLog(yesterday, "Fix the bike", model.Task)
+	assert.Nil(t, err)
+	_, err = logService.AddNewLog(today, "Call mum", model.Task)
+	assert.Nil(t, err)
+	_, err = logService.MigrateLog(dailyLog.Logs[0].Id, today)
+	assert.Nil(t, err)
+	migratedYesterday, err := os.ReadFile(logService.dayPath("17.10.2026"))
+	assert.Nil(t, err)
+
+	// The backup of the second file can't be written, so neither can the file.
+	backup := logService.backupPath(logService.dayPath("18.10.2026"), 1)
+	assert.Nil(t, os.Remove(backup))
+	assert.Nil(t, os.MkdirAll(path.Join(backup, "busy"), 0755))
+	_, err = logService.Undo()
+	assert.NotNil(t, err)
+	restored, err := os.ReadFile(logService.dayPath("17.10.2026"))
+	assert.Nil(t, err)
+	assert.Equal(t, string(migratedYesterday), string(restored))
+
+	// The command is still done, and can be undone once the file can be written.
+	assert.Nil(t, os.RemoveAll(backup))
+	command, err := logService.Undo()
+	assert.Nil(t, err)
+	assert.Equal(t, "migrate entry", command.Name)
+	dailyLog, err = logService.ReadDay(yesterday)
+	assert.Nil(t, err)
+	assert.Equal(t, model.Task, dailyLog.Logs[0].Mark)
+}
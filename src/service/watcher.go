@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	zerolog "github.com/rs/zerolog/log"
+)
+
+// DefaultWatchInterval is the time between two checks of the journal files.
+const DefaultWatchInterval = time.Second
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// ChangeEvent describes a day file changed by another process.
+type ChangeEvent struct {
+	Date time.Time
+	// Conflict is true when the day had changes that couldn't be saved, in that
+	// case the changes are kept and the file of the other process is stored
+	// next to the day with the .conflict.yaml extension.
+	Conflict bool
+}
+
+func statFile(filePath string) (fileStamp, bool) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fileStamp{}, false
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, true
+}
+
+func (m *LogService) dayPath(dateString string) string {
+	return path.Join(m.baseDir, fmt.Sprintf("%v.yaml", dateString))
+}
+
+// rememberStamp stores the version of the day file known by the service. The
+// lock must be held.
+func (m *LogService) rememberStamp(dateString string) {
+	if stamp, ok := statFile(m.dayPath(dateString)); ok {
+		m.stamps[dateString] = stamp
+	}
+}
+
+// Watch checks the journal files every interval until the context is done and
+// reloads the days changed by other processes, like the API of another
+// instance, a sync tool or a text editor. The changes of the service itself
+// are not reported.
+func (m *LogService) Watch(ctx context.Context, interval time.Duration, changed func(event ChangeEvent)) {
+	snapshot := m.scanStamps()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			var events []ChangeEvent
+			snapshot, events = m.poll(snapshot)
+			for _, event := range events {
+				changed(event)
+			}
+		}
+	}
+}
+
+// scanStamps returns the versions of the day files in the journal.
+func (m *LogService) scanStamps() map[string]fileStamp {
+	stamps := map[string]fileStamp{}
+	files, err := os.ReadDir(m.baseDir)
+	if err != nil {
+		zerolog.Print("Error reading the journal directory ", err)
+		return stamps
+	}
+	for _, file := range files {
+		filename := file.Name()
+		extension := filepath.Ext(filename)
+		if file.IsDir() || extension != ".yaml" {
+			continue
+		}
+		dateString := filename[0 : len(filename)-len(extension)]
+		if _, err := timeconv.StringToDayTime(dateString); err != nil {
+			continue
+		}
+		if info, err := file.Info(); err == nil {
+			stamps[dateString] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+// poll compares the day files with the previous snapshot and reloads the
+// changed ones, returning the new snapshot.
+func (m *LogService) poll(snapshot map[string]fileStamp) (map[string]fileStamp, []ChangeEvent) {
+	current := m.scanStamps()
+	var changed []string
+	for dateString, stamp := range current {
+		if previous, ok := snapshot[dateString]; !ok || previous != stamp {
+			changed = append(changed, dateString)
+		}
+	}
+	for dateString := range snapshot {
+		if _, ok := current[dateString]; !ok {
+			changed = append(changed, dateString)
+		}
+	}
+
+	var events []ChangeEvent
+	for _, dateString := range changed {
+		if event, ok := m.reload(dateString, current[dateString]); ok {
+			events = append(events, event)
+		}
+	}
+	return current, events
+}
+
+// reload drops the cached day when its file was changed by another process.
+func (m *LogService) reload(dateString string, stamp fileStamp) (ChangeEvent, bool) {
+	date, err := timeconv.StringToDayTime(dateString)
+	if err != nil {
+		return ChangeEvent{}, false
+	}
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	if known, ok := m.stamps[dateString]; ok && known == stamp {
+		return ChangeEvent{}, false
+	}
+	if m.dirty[dateString] {
+		conflictPath := path.Join(m.baseDir, fmt.Sprintf("%v.conflict.yaml", dateString))
+		if data, err := os.ReadFile(m.dayPath(dateString)); err == nil {
+			if err := os.WriteFile(conflictPath, data, 0666); err != nil {
+				zerolog.Print("Error saving the conflict file ", err)
+			}
+		}
+		m.stamps[dateString] = stamp
+		m.forgetIndexedDay(dateString)
+		return ChangeEvent{Date: date, Conflict: true}, true
+	}
+	delete(m.cache, dateString)
+	delete(m.stamps, dateString)
+	m.forgetIndexedDay(dateString)
+	return ChangeEvent{Date: date}, true
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollReloadsExternalChanges(t *testing.T) {
+	logService := NewLogService(t.TempDir())
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.Local)
+	dateString := timeconv.TimeToDayString(date)
+	_, err := logService.AddNewLog(date, "first", model.Task)
+	assert.Nil(t, err)
+
+	snapshot := logService.scanStamps()
+	_, err = logService.AddNewLog(date, "second", model.Task)
+	assert.Nil(t, err)
+	snapshot, events := logService.poll(snapshot)
+	assert.Empty(t, events, "the changes of the service are not reported")
+
+	external := "items:\n  - id: external\n    name: external\n    mark: note\n"
+	assert.Nil(t, os.WriteFile(logService.dayPath(dateString), []byte(external), 0666))
+	// Make sure the stamp changes even with a coarse file system clock.
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(logService.dayPath(dateString), later, later))
+	snapshot, events = logService.poll(snapshot)
+	assert.Len(t, events, 1)
+	assert.False(t, events[0].Conflict)
+	assert.Equal(t, dateString, timeconv.TimeToDayString(events[0].Date))
+
+	dailyLog, err := logService.ReadDay(date)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+	assert.Equal(t, "external", dailyLog.Logs[0].Name)
+
+	// A day with unsaved changes keeps them and stores the other version aside.
+	logService.mx.Lock()
+	logService.dirty[dateString] = true
+	logService.mx.Unlock()
+	evenLater := later.Add(time.Minute)
+	assert.Nil(t, os.Chtimes(logService.dayPath(dateString), evenLater, evenLater))
+	_, events = logService.poll(snapshot)
+	assert.Len(t, events, 1)
+	assert.True(t, events[0].Conflict)
+	_, err = os.Stat(logService.dayPath(dateString + ".conflict"))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	model2 "github.com/apoloa/bjournal/src/model"
+	"github.com/derailed/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	// habitCellWidth is the width of the column of a day.
+	habitCellWidth = 3
+	// habitNameWidth is the maximum width of the names of the habits.
+	habitNameWidth = 16
+	// habitStatsWidth is the width of the streak and the completion rate.
+	habitStatsWidth = 10
+)
+
+// HabitGrid displays the habits of the journal as rows with a column for each
+// of the days up to the last one, marking the days they were done, with their
+// streaks and completion rates.
+type HabitGrid struct {
+	*tview.Box
+
+	habits   []model2.Habit
+	checkIns map[string]model2.CheckIns
+
+	// last is the day of the last column.
+	last time.Time
+
+	// The index of the selected habit.
+	currentItem int
+
+	// The selected day, counted back from the last one.
+	currentDay int
+
+	// The style of the names of the habits.
+	nameStyle tcell.Style
+
+	// The styles of the days done and not done.
+	doneStyle    tcell.Style
+	notDoneStyle tcell.Style
+
+	// The style of the days and the stats.
+	statsStyle tcell.Style
+
+	// The style for selected items.
+	selectedStyle tcell.Style
+
+	// toggled is called with the habit and the day to check or uncheck.
+	toggled func(habit model2.Habit, date time.Time, done bool)
+}
+
+// NewHabitGrid returns a new habit grid ending on the day of last.
+func NewHabitGrid(last time.Time) *HabitGrid {
+	return &HabitGrid{
+		Box:          tview.NewBox(),
+		last:         last,
+		nameStyle:    tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor),
+		doneStyle:    tcell.StyleDefault.Foreground(tcell.ColorGreen),
+		notDoneStyle: tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
+		statsStyle:   tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
+		selectedStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimitiveBackgroundColor).
+			Background(tview.Styles.PrimaryTextColor),
+	}
+}
+
+// SetHabits sets the habits and the days they were done, by habit name.
+func (g *HabitGrid) SetHabits(habits []model2.Habit, checkIns map[string]model2.CheckIns) *HabitGrid {
+	g.habits = habits
+	g.checkIns = checkIns
+	if g.currentItem >= len(habits) {
+		g.currentItem = len(habits) - 1
+	}
+	if g.currentItem < 0 {
+		g.currentItem = 0
+	}
+	return g
+}
+
+// SetLast sets the day of the last column.
+func (g *HabitGrid) SetLast(last time.Time) *HabitGrid {
+	g.last = last
+	return g
+}
+
+// SetToggledFunc sets the function called to check or uncheck the selected
+// habit on the selected day.
+func (g *HabitGrid) SetToggledFunc(toggled func(habit model2.Habit, date time.Time, done bool)) *HabitGrid {
+	g.toggled = toggled
+	return g
+}
+
+// GetCurrentHabit returns the selected habit, false if there is none.
+func (g *HabitGrid) GetCurrentHabit() (model2.Habit, bool) {
+	if g.currentItem < 0 || g.currentItem >= len(g.habits) {
+		return model2.Habit{}, false
+	}
+	return g.habits[g.currentItem], true
+}
+
+// GetCurrentDay returns the selected day.
+func (g *HabitGrid) GetCurrentDay() time.Time {
+	return g.last.AddDate(0, 0, -g.currentDay)
+}
+
+// days returns the number of days that fit in the width.
+func (g *HabitGrid) days(width int) int {
+	days := (width - habitNameWidth - habitStatsWidth - 1) / habitCellWidth
+	if days < 1 {
+		return 1
+	}
+	return days
+}
+
+// Draw draws this primitive onto the screen.
+func (g *HabitGrid) Draw(screen tcell.Screen) {
+	g.Box.DrawForSubclass(screen, g)
+
+	x, y, width, height := g.GetInnerRect()
+	if len(g.habits) == 0 {
+		printWithStyle(screen, "No habits, add them with bj habit add", x+1, y, 0, width-1, AlignLeft, g.statsStyle, true)
+		return
+	}
+	days := g.days(width)
+	if g.currentDay >= days {
+		g.currentDay = days - 1
+	}
+	gridX := x + habitNameWidth + 1
+	first := g.last.AddDate(0, 0, 1-days)
+
+	// The header has the days of the month.
+	for column := 0; column < days; column++ {
+		date := first.AddDate(0, 0, column)
+		printWithStyle(screen, fmt.Sprintf("%2d", date.Day()), gridX+column*habitCellWidth, y, 0, habitCellWidth, AlignLeft, g.statsStyle, true)
+	}
+	printWithStyle(screen, "streak  %", x, y, 0, width-1, AlignRight, g.statsStyle, true)
+	y++
+
+	for index, habit := range g.habits {
+		if index+1 >= height {
+			break
+		}
+		checkIns := g.checkIns[habit.Name]
+		nameStyle := g.nameStyle
+		if index == g.currentItem {
+			nameStyle = g.selectedStyle
+		}
+		name := habit.Name
+		if utf8.RuneCountInString(name) > habitNameWidth-1 {
+			name = string([]rune(name)[:habitNameWidth-2]) + "…"
+		}
+		printWithStyle(screen, Escape(name), x+1, y, 0, habitNameWidth-1, AlignLeft, nameStyle, true)
+		for column := 0; column < days; column++ {
+			date := first.AddDate(0, 0, column)
+			cell, style := " · ", g.notDoneStyle
+			if checkIns.Has(date) {
+				cell, style = " ● ", g.doneStyle
+			}
+			if index == g.currentItem && column == days-1-g.currentDay {
+				style = g.selectedStyle
+			}
+			printWithStyle(screen, cell, gridX+column*habitCellWidth, y, 0, habitCellWidth, AlignLeft, style, true)
+		}
+		rate := habit.CompletionRate(checkIns, g.last.AddDate(0, 0, 1-model2.HabitRateDays), g.last)
+		stats := fmt.Sprintf("%d %3.0f%%", habit.Streak(checkIns, g.last), rate*100)
+		printWithStyle(screen, stats, x, y, 0, width-1, AlignRight, g.statsStyle, true)
+		y++
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (g *HabitGrid) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return g.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if len(g.habits) == 0 {
+			return
+		}
+		switch event.Key() {
+		case tcell.KeyDown:
+			g.currentItem++
+		case tcell.KeyUp:
+			g.currentItem--
+		case tcell.KeyLeft:
+			g.currentDay++
+		case tcell.KeyRight:
+			g.currentDay--
+		case tcell.KeyEnter:
+			g.toggle()
+		case tcell.KeyRune:
+			if event.Rune() == ' ' {
+				g.toggle()
+			}
+		}
+		// The grid always keeps a habit and a day selected.
+		if g.currentItem < 0 {
+			g.currentItem = 0
+		} else if g.currentItem >= len(g.habits) {
+			g.currentItem = len(g.habits) - 1
+		}
+		if g.currentDay < 0 {
+			g.currentDay = 0
+		}
+		_, _, width, _ := g.GetInnerRect()
+		if days := g.days(width); g.currentDay >= days {
+			g.currentDay = days - 1
+		}
+	})
+}
+
+// toggle checks the selected habit on the selected day, or unchecks it when
+// it was done.
+func (g *HabitGrid) toggle() {
+	habit, ok := g.GetCurrentHabit()
+	if !ok || g.toggled == nil {
+		return
+	}
+	date := g.GetCurrentDay()
+	g.toggled(habit, date, !g.checkIns[habit.Name].Has(date))
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	model2 "github.com/apoloa/bjournal/src/model"
+	"github.com/gdamore/tcell/v2"
+)
+
+// importantSignifier is drawn before the bullet of the important logs.
+const importantSignifier = "*"
+
+var importantStyle = tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+
+// ImportantMode is how a list orders the important logs.
+type ImportantMode int
+
+const (
+	// AllLogs draws the logs in their order.
+	AllLogs ImportantMode = iota
+	// ImportantFirst draws the important logs before their siblings.
+	ImportantFirst
+	// OnlyImportant draws only the important logs and their parents.
+	OnlyImportant
+)
+
+// Next returns the mode that follows, to cycle through them.
+func (m ImportantMode) Next() ImportantMode {
+	return (m + 1) % 3
+}
+
+func (m ImportantMode) String() string {
+	switch m {
+	case ImportantFirst:
+		return "important first"
+	case OnlyImportant:
+		return "only important"
+	}
+	return "all entries"
+}
+
+// hasImportant checks if the log or any of its sub logs is important.
+func hasImportant(log *model2.Log) bool {
+	if log.Important {
+		return true
+	}
+	if log.SubLogs != nil {
+		for index := range *log.SubLogs {
+			if hasImportant(&(*log.SubLogs)[index]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	model2 "github.com/apoloa/bjournal/src/model"
 	"github.com/derailed/tview"
@@ -20,10 +21,17 @@ type List struct {
 
 	daily *model2.DailyLog
 
-	// The index of the currently selected item.
-	currentItem int
+	// The rows drawn, the items and their sub logs that are not collapsed.
+	rows []listRow
 
-	secondaryIndex int
+	// The index of the currently selected row.
+	currentRow int
+
+	// The ids of the logs whose sub logs are hidden.
+	collapsed map[string]bool
+
+	// Whether the important logs are drawn first or alone.
+	importantMode ImportantMode
 
 	// The item main text style.
 	mainTextStyle tcell.Style
@@ -39,6 +47,25 @@ type List struct {
 
 	selectedStyleSubLog tcell.Style
 
+	// The style of the logs migrated more times than migrationWarning.
+	warningStyle tcell.Style
+
+	// The style of the signifier of the important logs.
+	importantStyle tcell.Style
+
+	// The style of the moments of the logs.
+	momentStyle tcell.Style
+
+	// The style of the tasks still open after they were due.
+	overdueStyle tcell.Style
+
+	// now returns the current time, to find the overdue tasks.
+	now func() time.Time
+
+	// The number of migrations after which a log is shown with the warning
+	// style, 0 disables it.
+	migrationWarning int
+
 	// If true, the selection is only shown when the list has focus.
 	selectedFocusOnly bool
 
@@ -79,8 +106,8 @@ func NewList() *List {
 		Box:                tview.NewBox(),
 		wrapAround:         false,
 		highlightFullLine:  true,
-		currentItem:        -1,
-		secondaryIndex:     -1,
+		currentRow:         -1,
+		collapsed:          map[string]bool{},
 		mainTextStyle:      tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor),
 		secondaryTextStyle: tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
 		shortcutStyle:      tcell.StyleDefault.Foreground(tview.Styles.SecondaryTextColor),
@@ -88,7 +115,135 @@ func NewList() *List {
 			Background(tview.Styles.PrimaryTextColor),
 		selectedStyleSubLog: tcell.StyleDefault.Foreground(tview.Styles.PrimitiveBackgroundColor).
 			Background(tview.Styles.PrimaryTextColor),
+		warningStyle:   tcell.StyleDefault.Foreground(tcell.ColorOrangeRed),
+		importantStyle: importantStyle,
+		momentStyle:    tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
+		overdueStyle:   tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true),
+		now:            time.Now,
+	}
+}
+
+// SetMigrationWarning shows the logs migrated more than the given times with
+// the warning style, 0 disables it.
+func (l *List) SetMigrationWarning(migrations int) *List {
+	l.migrationWarning = migrations
+	return l
+}
+
+// textStyle returns the style of the text of the log.
+func (l *List) textStyle(log *model2.Log) tcell.Style {
+	if log.IsOverdue(l.now()) {
+		return l.overdueStyle
+	}
+	if l.migrationWarning > 0 && log.Migrations > l.migrationWarning {
+		return l.warningStyle
 	}
+	return l.mainTextStyle
+}
+
+// momentLabel returns when the log is due or happens, the time alone when it
+// is the day of the list.
+func (l *List) momentLabel(log *model2.Log) string {
+	moment := log.Moment()
+	if moment == nil {
+		return ""
+	}
+	if l.daily != nil && moment.SameDay(l.daily.Date) {
+		return moment.Clock()
+	}
+	label := moment.Time.Format("02.01")
+	if moment.Time.Year() != l.now().Year() {
+		label = moment.Time.Format("02.01.06")
+	}
+	if clock := moment.Clock(); clock != "" {
+		label += " " + clock
+	}
+	return label
+}
+
+// listDepthIndent is the number of cells each level of sub logs is indented.
+const listDepthIndent = 3
+
+// listRow is a log drawn in the list.
+type listRow struct {
+	log   *model2.Log
+	depth int
+	// item is the index of the item the log belongs to.
+	item int
+}
+
+// SetCollapsed sets the ids of the logs whose sub logs are hidden. The map is
+// updated when the user expands or collapses a log, so it can be shared by the
+// lists that replace this one.
+func (l *List) SetCollapsed(collapsed map[string]bool) *List {
+	l.collapsed = collapsed
+	l.flatten()
+	return l
+}
+
+// SetImportantMode draws the important logs first or alone, at every level.
+func (l *List) SetImportantMode(mode ImportantMode) *List {
+	l.importantMode = mode
+	l.flatten()
+	return l
+}
+
+// flatten builds the rows from the items, keeping the selected log.
+func (l *List) flatten() {
+	var current *model2.Log
+	if l.currentRow >= 0 && l.currentRow < len(l.rows) {
+		current = l.rows[l.currentRow].log
+	}
+	l.rows = nil
+	for _, index := range l.arrange(l.items) {
+		l.addRows(l.items[index], 0, index)
+	}
+	if l.currentRow >= len(l.rows) {
+		l.currentRow = len(l.rows) - 1
+	}
+	for index, row := range l.rows {
+		if row.log == current {
+			l.currentRow = index
+		}
+	}
+}
+
+func (l *List) addRows(log *model2.Log, depth, item int) {
+	l.rows = append(l.rows, listRow{log: log, depth: depth, item: item})
+	if log.SubLogs == nil || l.collapsed[log.Id] {
+		return
+	}
+	subLogs := make([]*model2.Log, len(*log.SubLogs))
+	for index := range *log.SubLogs {
+		subLogs[index] = &(*log.SubLogs)[index]
+	}
+	for _, index := range l.arrange(subLogs) {
+		l.addRows(subLogs[index], depth+1, item)
+	}
+}
+
+// arrange returns the indexes of the logs in the order they are drawn,
+// following the important mode.
+func (l *List) arrange(logs []*model2.Log) []int {
+	var important, others []int
+	for index, log := range logs {
+		switch {
+		case l.importantMode == ImportantFirst && log.Important:
+			important = append(important, index)
+		case l.importantMode == OnlyImportant:
+			if hasImportant(log) {
+				important = append(important, index)
+			}
+		default:
+			others = append(others, index)
+		}
+	}
+	return append(important, others...)
+}
+
+// hasSubLogs checks if the log has sub logs to expand or collapse.
+func hasSubLogs(log *model2.Log) bool {
+	return log.SubLogs != nil && len(*log.SubLogs) > 0
 }
 
 // SetCurrentItem sets the currently selected item by its index, starting at 0
@@ -105,23 +260,34 @@ func (l *List) SetCurrentItem(index int) *List {
 		index = len(l.items) - 1
 	}
 	if index < 0 {
-		index = -1
+		l.currentRow = -1
+		return l
 	}
-
-	if index != l.currentItem && l.changed != nil {
-		item := l.items[index]
-		l.changed(index, item)
+	for row := range l.rows {
+		if l.rows[row].item == index {
+			l.setCurrentRow(row)
+			break
+		}
 	}
-
-	l.currentItem = index
-
 	return l
 }
 
-// GetCurrentItem returns the index of the currently selected list item,
-// starting at 0 for the first item.
+// setCurrentRow selects the row, triggering a "changed" event if the
+// selection changes.
+func (l *List) setCurrentRow(row int) {
+	if row != l.currentRow && row >= 0 && row < len(l.rows) && l.changed != nil {
+		l.changed(l.rows[row].item, l.rows[row].log)
+	}
+	l.currentRow = row
+}
+
+// GetCurrentItem returns the index of the item of the currently selected log,
+// starting at 0 for the first item, or -1 if there is none.
 func (l *List) GetCurrentItem() int {
-	return l.currentItem
+	if l.currentRow < 0 || l.currentRow >= len(l.rows) {
+		return -1
+	}
+	return l.rows[l.currentRow].item
 }
 
 // SetOffset sets the number of items to be skipped (vertically) as well as the
@@ -169,24 +335,20 @@ func (l *List) RemoveItem(index int) *List {
 		index = -1
 	}
 
-	// Remove item.
+	// Remove item, the selection moves to the previous row when it was in it.
+	removed := l.GetCurrentItem() == index
 	l.items = append(l.items[:index], l.items[index+1:]...)
-
-	// If there is nothing left, we're done.
-	if len(l.items) == 0 {
-		return l
-	}
-
-	// Shift current item.
-	previousCurrentItem := l.currentItem
-	if l.currentItem >= index {
-		l.currentItem--
+	for row := range l.rows {
+		if l.rows[row].item == index {
+			l.currentRow = row - 1
+			break
+		}
 	}
+	l.flatten()
 
 	// Fire "changed" event for removed items.
-	if previousCurrentItem == index && l.changed != nil {
-		item := l.items[l.currentItem]
-		l.changed(l.currentItem, item)
+	if removed && l.currentRow >= 0 && l.changed != nil {
+		l.changed(l.rows[l.currentRow].item, l.rows[l.currentRow].log)
 	}
 
 	return l
@@ -315,6 +477,7 @@ func (l *List) AddDailyLog(dailyLog *model2.DailyLog) *List {
 		l.InsertItem(-1, &dailyLog.Logs[index], nil)
 	}
 	l.daily = dailyLog
+	l.flatten()
 	return l
 }
 
@@ -357,17 +520,13 @@ func (l *List) InsertItem(index int, item *model2.Log, selected func()) *List {
 		index = len(l.items)
 	}
 
-	// Shift current item.
-	if l.currentItem < len(l.items) && l.currentItem >= index {
-		l.currentItem++
-	}
-
 	// Insert item (make space for the new item, then shift and insert).
 	l.items = append(l.items, nil)
 	if index < len(l.items)-1 { // -1 because l.items has already grown by one item.
 		copy(l.items[index+1:], l.items[index:])
 	}
 	l.items[index] = item
+	l.flatten()
 
 	return l
 }
@@ -392,39 +551,78 @@ func (l *List) GetDaily() *model2.DailyLog {
 	return l.daily
 }
 
+// GetCurrentLog returns the selected log, at any depth, or nil if there is none.
 func (l *List) GetCurrentLog() *model2.Log {
-	if l.currentItem < 0 {
-		return nil
-	} else if l.currentItem > len(l.items) {
+	if l.currentRow < 0 || l.currentRow >= len(l.rows) {
 		return nil
 	}
+	return l.rows[l.currentRow].log
+}
 
-	item := l.items[l.currentItem]
-
-	if item.SubLogs == nil {
-		return item
+// SelectLog selects the log or sub log with the given id, if it is in the list,
+// expanding the logs that hide it.
+func (l *List) SelectLog(id string) *List {
+	for _, item := range l.items {
+		if l.expandPath(item, id) {
+			break
+		}
 	}
+	l.flatten()
+	for row := range l.rows {
+		if l.rows[row].log.Id == id {
+			l.currentRow = row
+			break
+		}
+	}
+	return l
+}
 
-	if l.secondaryIndex < 0 {
-		return item
-	} else if l.secondaryIndex > len(*item.SubLogs) {
-		return item
+// expandPath expands the logs that contain the log with the id, returning
+// false if it is not in the log.
+func (l *List) expandPath(log *model2.Log, id string) bool {
+	if log.Id == id {
+		return true
 	}
+	if log.SubLogs == nil {
+		return false
+	}
+	for index := range *log.SubLogs {
+		if l.expandPath(&(*log.SubLogs)[index], id) {
+			delete(l.collapsed, log.Id)
+			return true
+		}
+	}
+	return false
+}
 
-	return &(*item.SubLogs)[l.secondaryIndex]
+// SetExpanded shows or hides the sub logs of the selected log.
+func (l *List) SetExpanded(expanded bool) *List {
+	log := l.GetCurrentLog()
+	if log == nil || !hasSubLogs(log) {
+		return l
+	}
+	if expanded {
+		delete(l.collapsed, log.Id)
+	} else {
+		l.collapsed[log.Id] = true
+	}
+	l.flatten()
+	return l
 }
 
 // SetItemText sets an item's main and secondary text. Panics if the index is
 // out of range.
 func (l *List) SetItemText(index int, log *model2.Log) *List {
 	l.items[index] = log
+	l.flatten()
 	return l
 }
 
 // Clear removes all items from the list.
 func (l *List) Clear() *List {
 	l.items = nil
-	l.currentItem = 0
+	l.rows = nil
+	l.currentRow = -1
 	return l
 }
 
@@ -454,18 +652,14 @@ func (l *List) Draw(screen tcell.Screen) {
 		bottomLimit = totalHeight
 	}
 
-	x += 4
-	width -= 4
+	x += 5
+	width -= 5
 
 	// Adjust offset to keep the current selection in view.
-	if l.currentItem < l.itemOffset {
-		l.itemOffset = l.currentItem
-	} else if 2*(l.currentItem-l.itemOffset) >= height-1 {
-		l.itemOffset = (2*l.currentItem + 3 - height) / 2
-	} else {
-		if l.currentItem-l.itemOffset >= height {
-			l.itemOffset = l.currentItem + 1 - height
-		}
+	if l.currentRow >= 0 && l.currentRow < l.itemOffset {
+		l.itemOffset = l.currentRow
+	} else if l.currentRow-l.itemOffset >= height {
+		l.itemOffset = l.currentRow + 1 - height
 	}
 	if l.horizontalOffset < 0 {
 		l.horizontalOffset = 0
@@ -477,7 +671,7 @@ func (l *List) Draw(screen tcell.Screen) {
 		overflowing bool // Whether a text's end exceeds the right border.
 	)
 
-	for index, item := range l.items {
+	for index, row := range l.rows {
 		if index < l.itemOffset {
 			continue
 		}
@@ -486,70 +680,63 @@ func (l *List) Draw(screen tcell.Screen) {
 			break
 		}
 
-		// Shortcuts.
-		printWithStyle(screen, fmt.Sprintf("(%s)", string(item.Mark.Print())), x-5, y, 0, 4, AlignRight, item.Mark.Style(), true)
+		// Each level of sub logs is indented.
+		indent := listDepthIndent * row.depth
+		if indent > width-1 {
+			indent = width - 1
+		}
+		log := row.log
+
+		// Signifier and bullet.
+		if log.Important {
+			printWithStyle(screen, importantSignifier, x-5+indent, y, 0, 1, AlignLeft, l.importantStyle, true)
+		}
+		printWithStyle(screen, fmt.Sprintf("(%s)", string(log.Mark.Print())), x-4+indent, y, 0, 3, AlignLeft, log.Mark.Style(), true)
 
-		// Main text.
-		for _, wordWrap := range WordWrap(item.Name, width) {
-			printWithStyle(screen, wordWrap, x, y, l.horizontalOffset, width, AlignLeft, l.mainTextStyle, true)
+		// Main text, with the number of hidden sub logs.
+		name := highlightTags(log.Name)
+		if l.collapsed[log.Id] && hasSubLogs(log) {
+			name = fmt.Sprintf("%v [+%d]", name, log.CountSubLogs())
+		}
+		// The moment is right aligned on the first line.
+		nameWidth := width - indent
+		if moment := l.momentLabel(log); moment != "" && y < bottomLimit {
+			style := l.momentStyle
+			if log.IsOverdue(l.now()) {
+				style = l.overdueStyle
+			}
+			printWithStyle(screen, moment, x+indent, y, 0, width-indent, AlignRight, style, true)
+			if nameWidth > len(moment)+1 {
+				nameWidth -= len(moment) + 1
+			}
+		}
+		for _, wordWrap := range WordWrap(name, nameWidth) {
+			if y >= bottomLimit {
+				break
+			}
+			printWithStyle(screen, wordWrap, x+indent, y, l.horizontalOffset, nameWidth, AlignLeft, l.textStyle(log), true)
 			// Background color of selected text.
-			if index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus()) {
-				textWidth := width
+			if index == l.currentRow && (!l.selectedFocusOnly || l.HasFocus()) {
+				textWidth := width - indent
 				if !l.highlightFullLine {
-					if w := TaggedStringWidth(item.Name); w < textWidth {
+					if w := TaggedStringWidth(name); w < textWidth {
 						textWidth = w
 					}
 				}
 
 				mainTextColor, _, _ := l.mainTextStyle.Decompose()
 				for bx := 0; bx < textWidth; bx++ {
-					m, c, style, _ := screen.GetContent(x+bx, y)
+					m, c, style, _ := screen.GetContent(x+indent+bx, y)
 					fg, _, _ := style.Decompose()
 					style = l.selectedStyle
 					if fg != mainTextColor {
 						style = style.Foreground(fg)
 					}
-					screen.SetContent(x+bx, y, m, c, style)
+					screen.SetContent(x+indent+bx, y, m, c, style)
 				}
 			}
 			y++
 		}
-
-		if y >= bottomLimit {
-			break
-		}
-
-		if item.SubLogs == nil {
-			continue
-		}
-
-		for subIndex, sublog := range *item.SubLogs {
-			printWithStyle(screen, fmt.Sprintf("(%s)", string(sublog.Mark.Print())), x-2, y, 0, 4, AlignRight, sublog.Mark.Style(), true)
-			for _, wordWrap := range WordWrap(sublog.Name, width) {
-				printWithStyle(screen, wordWrap, x+3, y, l.horizontalOffset, width, AlignLeft, l.mainTextStyle, true)
-				// Background color of selected text.
-				if (index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus())) && subIndex == l.secondaryIndex {
-					textWidth := width
-					if !l.highlightFullLine {
-						if w := TaggedStringWidth(item.Name); w < textWidth {
-							textWidth = w
-						}
-					}
-
-					mainTextColor, _, _ := l.mainTextStyle.Decompose()
-					for bx := 0; bx < textWidth; bx++ {
-						m, c, style, _ := screen.GetContent(x+3+bx, y)
-						fg, _, _ := style.Decompose()
-						style = l.selectedStyle
-						if fg != mainTextColor {
-							style = style.Foreground(fg)
-						}
-						screen.SetContent(x+3+bx, y, m, c, style)
-					}
-				}
-				y++
-			}
-		}
 	}
 
 	// We don't want the item text to get out of view. If the horizontal offset
@@ -562,72 +749,15 @@ func (l *List) Draw(screen tcell.Screen) {
 	l.overflowing = overflowing
 }
 
-func (l *List) increaseIndex() {
-	if l.currentItem == len(l.items) {
-		l.currentItem = -1
-		return
-	}
-	if l.currentItem >= 0 && l.currentItem < len(l.items) {
-		if l.GetItem(l.currentItem).SubLogs != nil {
-			sublogs := l.GetItem(l.currentItem).SubLogs
-			if len(*sublogs) > 0 && l.secondaryIndex < len(*sublogs)-1 {
-				l.secondaryIndex++
-			} else {
-				l.secondaryIndex = -1
-				if l.currentItem > len(l.items)-1 {
-					l.currentItem = -1
-				} else {
-					l.currentItem++
-				}
-
-			}
-
-		} else {
-			if l.currentItem > len(l.items)-1 {
-				l.currentItem = -1
-				l.secondaryIndex = -1
-			} else {
-				l.currentItem++
-				l.secondaryIndex = -1
-			}
-
-		}
-	} else {
-		l.currentItem++
-		l.secondaryIndex = -1
-	}
-}
-
-func (l *List) decreaseIndex() {
-	if l.currentItem == -1 {
-		l.currentItem = len(l.items) - 1
-		if l.GetItem(l.currentItem).SubLogs != nil {
-			l.secondaryIndex = len(*(l.GetItem(l.currentItem)).SubLogs) - 1
-		}
-	} else if l.GetItem(l.currentItem).SubLogs != nil {
-		sublogs := l.GetItem(l.currentItem).SubLogs
-		if len(*sublogs) > 0 && l.secondaryIndex < len(*sublogs) {
-			if l.secondaryIndex == -1 {
-				l.currentItem--
-				if l.currentItem == -1 {
-					return
-				}
-				if l.GetItem(l.currentItem).SubLogs != nil {
-					l.secondaryIndex = len(*(l.GetItem(l.currentItem)).SubLogs) - 1
-				}
-				return
-			}
-			l.secondaryIndex--
-		}
-	} else {
-		l.currentItem--
-		if l.currentItem == -1 {
-			return
-		}
-		if l.GetItem(l.currentItem).SubLogs != nil {
-			l.secondaryIndex = len(*(l.GetItem(l.currentItem)).SubLogs) - 1
+// parentRow returns the row of the parent of the log of the row, -1 for the
+// items.
+func (l *List) parentRow(row int) int {
+	for parent := row - 1; parent >= 0; parent-- {
+		if l.rows[parent].depth < l.rows[row].depth {
+			return parent
 		}
 	}
+	return -1
 }
 
 // InputHandler returns the handler for this primitive.
@@ -638,90 +768,107 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.
 				l.done()
 			}
 			return
-		} else if len(l.items) == 0 {
+		} else if len(l.rows) == 0 {
 			return
 		}
 
-		previousItem := l.currentItem
+		previousRow := l.currentRow
 
 		switch key := event.Key(); key {
 		case tcell.KeyTab, tcell.KeyDown:
-			l.increaseIndex()
+			l.currentRow++
 		case tcell.KeyBacktab, tcell.KeyUp:
-			l.decreaseIndex()
+			if l.currentRow == -1 {
+				l.currentRow = len(l.rows) - 1
+			} else {
+				l.currentRow--
+			}
 		case tcell.KeyRight:
+			// Expand the log, or move into its first sub log when it is
+			// already expanded.
 			if l.overflowing {
 				l.horizontalOffset += 2 // We shift by 2 to account for two-cell characters.
-			} else {
-				l.currentItem++
+			} else if log := l.GetCurrentLog(); log != nil && hasSubLogs(log) {
+				if l.collapsed[log.Id] {
+					l.SetExpanded(true)
+				} else {
+					l.currentRow++
+				}
 			}
 		case tcell.KeyLeft:
+			// Collapse the log, or move to its parent when it is already
+			// collapsed.
 			if l.horizontalOffset > 0 {
 				l.horizontalOffset -= 2
-			} else {
-				l.currentItem--
+			} else if log := l.GetCurrentLog(); log != nil {
+				if hasSubLogs(log) && !l.collapsed[log.Id] {
+					l.SetExpanded(false)
+				} else if parent := l.parentRow(l.currentRow); parent >= 0 {
+					l.currentRow = parent
+				}
 			}
 		case tcell.KeyHome:
-			l.currentItem = 0
+			l.currentRow = 0
 		case tcell.KeyEnd:
-			l.currentItem = len(l.items) - 1
+			l.currentRow = len(l.rows) - 1
 		case tcell.KeyPgDn:
 			_, _, _, height := l.GetInnerRect()
-			l.currentItem += height
-			if l.currentItem >= len(l.items) {
-				l.currentItem = len(l.items) - 1
+			l.currentRow += height
+			if l.currentRow >= len(l.rows) {
+				l.currentRow = len(l.rows) - 1
 			}
 		case tcell.KeyPgUp:
 			_, _, _, height := l.GetInnerRect()
-			l.currentItem -= height
-			if l.currentItem < 0 {
-				l.currentItem = 0
+			l.currentRow -= height
+			if l.currentRow < 0 {
+				l.currentRow = 0
 			}
 		case tcell.KeyEnter:
-			if l.currentItem >= 0 && l.currentItem < len(l.items) {
-				item := l.items[l.currentItem]
+			if l.currentRow >= 0 && l.currentRow < len(l.rows) {
+				row := l.rows[l.currentRow]
 				if l.selected != nil {
-					l.selected(l.currentItem, item)
+					l.selected(row.item, row.log)
+				}
+			}
+		case tcell.KeyRune:
+			if event.Rune() == ' ' {
+				if log := l.GetCurrentLog(); log != nil {
+					l.SetExpanded(l.collapsed[log.Id])
 				}
 			}
 		}
 
-		if l.currentItem < 0 {
+		if l.currentRow < 0 {
 			if l.wrapAround {
-				l.currentItem = len(l.items) - 1
+				l.currentRow = len(l.rows) - 1
 			} else {
-				l.currentItem = -1
+				l.currentRow = -1
 			}
-		} else if l.currentItem >= len(l.items) {
+		} else if l.currentRow >= len(l.rows) {
 			if l.wrapAround {
-				l.currentItem = 0
+				l.currentRow = 0
 			} else {
-				l.currentItem = -1
+				l.currentRow = -1
 			}
 		}
 
-		if l.currentItem != previousItem && l.currentItem < len(l.items) && l.changed != nil {
-			item := l.items[l.currentItem]
-			l.changed(l.currentItem, item)
+		if l.currentRow != previousRow && l.currentRow >= 0 && l.changed != nil {
+			row := l.rows[l.currentRow]
+			l.changed(row.item, row.log)
 		}
 	})
 }
 
-// indexAtPoint returns the index of the list item found at the given position
-// or a negative value if there is no such list item.
-func (l *List) indexAtPoint(x, y int) int {
+// rowAtPoint returns the index of the row found at the given position or a
+// negative value if there is no such row.
+func (l *List) rowAtPoint(x, y int) int {
 	rectX, rectY, width, height := l.GetInnerRect()
 	if rectX < 0 || rectX >= rectX+width || y < rectY || y >= rectY+height {
 		return -1
 	}
 
-	index := y - rectY
-
-	index /= 2
-
-	index += l.itemOffset
-
-	if index >= len(l.items) {
+	index := y - rectY + l.itemOffset
+	if index >= len(l.rows) {
 		return -1
 	}
 	return index
@@ -738,16 +885,13 @@ func (l *List) MouseHandler() func(action tview.MouseAction, event *tcell.EventM
 		switch action {
 		case tview.MouseLeftClick:
 			setFocus(l)
-			index := l.indexAtPoint(event.Position())
+			index := l.rowAtPoint(event.Position())
 			if index != -1 {
-				item := l.items[index]
+				row := l.rows[index]
 				if l.selected != nil {
-					l.selected(index, item)
-				}
-				if index != l.currentItem && l.changed != nil {
-					l.changed(index, item)
+					l.selected(row.item, row.log)
 				}
-				l.currentItem = index
+				l.setCurrentRow(index)
 			}
 			consumed = true
 		case tview.MouseScrollUp:
@@ -756,8 +900,7 @@ func (l *List) MouseHandler() func(action tview.MouseAction, event *tcell.EventM
 			}
 			consumed = true
 		case tview.MouseScrollDown:
-			lines := len(l.items) - l.itemOffset
-			lines *= 2
+			lines := len(l.rows) - l.itemOffset
 			if _, _, _, height := l.GetInnerRect(); lines > height {
 				l.itemOffset++
 			}
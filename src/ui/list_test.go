@@ -2,8 +2,12 @@ package ui
 
 import (
 	"testing"
+	"time"
 
 	model2 "github.com/apoloa/bjournal/src/model"
+	"github.com/derailed/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestIncreaseIndex(t *testing.T) {
@@ -12,3 +16,102 @@ func TestIncreaseIndex(t *testing.T) {
 	list.AddItem(&log1, nil)
 
 }
+
+func TestTreeNavigation(t *testing.T) {
+	trip := model2.NewLog("Trip", model2.Note)
+	bike := model2.NewLog("Bike", model2.Task)
+	bike.AppendNewSubLog("Tube", model2.Task)
+	trip.AppendSubLog(bike)
+	dailyLog := model2.DailyLog{}
+	dailyLog.SetLogs([]model2.Log{trip, model2.NewLog("Pump", model2.Task)})
+
+	collapsed := map[string]bool{}
+	list := NewList().SetCollapsed(collapsed).AddDailyLog(&dailyLog)
+	handler := list.InputHandler()
+	press := func(key tcell.Key, r rune) {
+		handler(tcell.NewEventKey(key, r, tcell.ModNone), func(tview.Primitive) {})
+	}
+	name := func() string {
+		return list.GetCurrentLog().Name
+	}
+
+	assert.Len(t, list.rows, 4)
+	list.SetCurrentItem(0)
+	press(tcell.KeyRight, 0)
+	assert.Equal(t, "Bike", name())
+	press(tcell.KeyDown, 0)
+	assert.Equal(t, "Tube", name())
+	assert.Equal(t, 0, list.GetCurrentItem())
+	press(tcell.KeyLeft, 0)
+	assert.Equal(t, "Bike", name())
+
+	// Collapsing hides the sub logs, and moving left again goes to the parent.
+	press(tcell.KeyLeft, 0)
+	assert.True(t, collapsed[(*dailyLog.Logs[0].SubLogs)[0].Id])
+	assert.Len(t, list.rows, 3)
+	press(tcell.KeyLeft, 0)
+	assert.Equal(t, "Trip", name())
+	press(tcell.KeyRune, ' ')
+	assert.Len(t, list.rows, 2)
+	press(tcell.KeyDown, 0)
+	assert.Equal(t, "Pump", name())
+	assert.Equal(t, 1, list.GetCurrentItem())
+
+	// Selecting a hidden log expands its parents.
+	list.SelectLog((*(*dailyLog.Logs[0].SubLogs)[0].SubLogs)[0].Id)
+	assert.Equal(t, "Tube", name())
+	assert.Len(t, list.rows, 4)
+}
+
+func TestImportantMode(t *testing.T) {
+	trip := model2.NewLog("Trip", model2.Note)
+	trip.AppendNewSubLog("Bike", model2.Task)
+	pump := model2.NewLog("Pump", model2.Task)
+	pump.Important = true
+	trip.AppendSubLog(pump)
+	rent := model2.NewLog("Rent", model2.Task)
+	rent.Important = true
+	dailyLog := model2.DailyLog{}
+	dailyLog.SetLogs([]model2.Log{trip, model2.NewLog("Call", model2.Task), rent})
+	list := NewList().AddDailyLog(&dailyLog)
+	names := func() []string {
+		var names []string
+		for _, row := range list.rows {
+			names = append(names, row.log.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"Trip", "Bike", "Pump", "Call", "Rent"}, names())
+	list.SetImportantMode(ImportantFirst)
+	assert.Equal(t, []string{"Rent", "Trip", "Pump", "Bike", "Call"}, names())
+	list.SetImportantMode(OnlyImportant)
+	assert.Equal(t, []string{"Trip", "Pump", "Rent"}, names())
+	assert.Equal(t, 2, list.rows[2].item)
+	assert.Equal(t, AllLogs, OnlyImportant.Next())
+}
+
+func TestHighlightTags(t *testing.T) {
+	assert.Equal(t, "Call [dodgerblue]#work[-], [mediumorchid]@home[-] me@mail.com", highlightTags("Call #work, @home me@mail.com"))
+	assert.Equal(t, "[red[] #", highlightTags("[red] #"))
+}
+
+func TestMomentLabel(t *testing.T) {
+	day := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.Local)
+	dailyLog := model2.DailyLog{Date: day}
+	list := NewList().AddDailyLog(&dailyLog)
+	list.now = func() time.Time { return day.Add(12 * time.Hour) }
+	label := func(text string, mark model2.Category) string {
+		name, moment, err := model2.ParseMomentText(text, day)
+		assert.Nil(t, err)
+		log := model2.NewLog(name, mark)
+		log.SetMoment(moment)
+		return list.momentLabel(&log)
+	}
+
+	assert.Equal(t, "14:00", label("Dentist ^14:00", model2.Event))
+	assert.Equal(t, "", label("Report ^today", model2.Task))
+	assert.Equal(t, "20.10 09:30", label("Standup ^2026-10-20 9:30", model2.Event))
+	assert.Equal(t, "05.01.27", label("Taxes ^2027-01-05", model2.Task))
+	assert.Equal(t, "", label("Nothing", model2.Task))
+}
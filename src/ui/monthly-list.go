@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	model2 "github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/utils"
+	"github.com/derailed/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// monthlyRow is a line of the monthly spread: a day of the calendar, an event
+// of a day, the header of the tasks or a task.
+type monthlyRow struct {
+	day    int
+	log    *model2.Log
+	header bool
+}
+
+// MonthlyList displays the calendar of a month with its events followed by
+// the task list of the month.
+type MonthlyList struct {
+	*tview.Box
+
+	monthly *model2.MonthlyLog
+
+	rows []monthlyRow
+
+	// The index of the currently selected row.
+	currentItem int
+
+	// The item main text style.
+	mainTextStyle tcell.Style
+
+	// The style of the days of the calendar.
+	dayStyle tcell.Style
+
+	// The style of the current day.
+	todayStyle tcell.Style
+
+	// The style for selected items.
+	selectedStyle tcell.Style
+
+	// The number of rows skipped at the top before the first row is drawn.
+	itemOffset int
+}
+
+// NewMonthlyList returns a new monthly list.
+func NewMonthlyList() *MonthlyList {
+	return &MonthlyList{
+		Box:           tview.NewBox(),
+		currentItem:   -1,
+		mainTextStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor),
+		dayStyle:      tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
+		todayStyle:    tcell.StyleDefault.Foreground(tcell.ColorBlue).Bold(true),
+		selectedStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimitiveBackgroundColor).
+			Background(tview.Styles.PrimaryTextColor),
+	}
+}
+
+// AddMonthlyLog sets the month shown by the list.
+func (l *MonthlyList) AddMonthlyLog(monthly *model2.MonthlyLog) *MonthlyList {
+	l.monthly = monthly
+	l.rows = nil
+	for day := 1; day <= monthly.DaysInMonth(); day++ {
+		l.rows = append(l.rows, monthlyRow{day: day})
+		logs := monthly.DayLogs(day)
+		for i := range logs {
+			l.rows = append(l.rows, monthlyRow{day: day, log: &logs[i]})
+		}
+	}
+	l.rows = append(l.rows, monthlyRow{header: true})
+	for i := range monthly.Logs {
+		l.rows = append(l.rows, monthlyRow{log: &monthly.Logs[i]})
+	}
+	return l
+}
+
+// GetMonthly returns the month shown by the list.
+func (l *MonthlyList) GetMonthly() *model2.MonthlyLog {
+	return l.monthly
+}
+
+// GetCurrentDay returns the day of the calendar of the selected row, or 0
+// when the selection is in the task list.
+func (l *MonthlyList) GetCurrentDay() int {
+	if l.currentItem < 0 || l.currentItem >= len(l.rows) {
+		return 0
+	}
+	return l.rows[l.currentItem].day
+}
+
+// GetCurrentLog returns the selected log, or nil when a day is selected.
+func (l *MonthlyList) GetCurrentLog() *model2.Log {
+	if l.currentItem < 0 || l.currentItem >= len(l.rows) {
+		return nil
+	}
+	return l.rows[l.currentItem].log
+}
+
+// SelectLog selects the row of the log with the given id, if any.
+func (l *MonthlyList) SelectLog(id string) *MonthlyList {
+	for i, row := range l.rows {
+		if row.log != nil && row.log.Id == id {
+			l.currentItem = i
+		}
+	}
+	return l
+}
+
+// SelectDay selects the row of the day of the calendar.
+func (l *MonthlyList) SelectDay(day int) *MonthlyList {
+	for i, row := range l.rows {
+		if row.day == day && row.log == nil {
+			l.currentItem = i
+		}
+	}
+	return l
+}
+
+// Draw draws this primitive onto the screen.
+func (l *MonthlyList) Draw(screen tcell.Screen) {
+	l.Box.DrawForSubclass(screen, l)
+
+	x, y, width, height := l.GetInnerRect()
+	bottomLimit := y + height
+	_, totalHeight := screen.Size()
+	if bottomLimit > totalHeight {
+		bottomLimit = totalHeight
+	}
+
+	// Adjust offset to keep the current selection in view.
+	if l.currentItem >= 0 && l.currentItem < l.itemOffset {
+		l.itemOffset = l.currentItem
+	} else if l.currentItem-l.itemOffset >= height {
+		l.itemOffset = l.currentItem + 1 - height
+	}
+
+	now := time.Now()
+	for index, row := range l.rows {
+		if index < l.itemOffset {
+			continue
+		}
+		if y >= bottomLimit {
+			break
+		}
+		switch {
+		case row.header:
+			printWithStyle(screen, "Tasks", x+1, y, 0, width-1, AlignLeft, l.dayStyle.Bold(true), true)
+		case row.log != nil:
+			offset := 8
+			if row.day == 0 {
+				offset = 1
+			}
+			printWithStyle(screen, fmt.Sprintf("(%s)", string(row.log.Mark.Print())), x+offset, y, 0, 3, AlignLeft, row.log.Mark.Style(), true)
+			printWithStyle(screen, row.log.Name, x+offset+4, y, 0, width-offset-4, AlignLeft, l.mainTextStyle, true)
+		default:
+			date := l.monthly.DayDate(row.day)
+			style := l.dayStyle
+			if date.Year() == now.Year() && date.YearDay() == now.YearDay() {
+				style = l.todayStyle
+			}
+			printWithStyle(screen, fmt.Sprintf("%02d %v", row.day, utils.ToShortString(date.Weekday())), x+1, y, 0, width-1, AlignLeft, style, true)
+		}
+
+		if index == l.currentItem {
+			for bx := 0; bx < width; bx++ {
+				m, c, style, _ := screen.GetContent(x+bx, y)
+				fg, _, _ := style.Decompose()
+				screen.SetContent(x+bx, y, m, c, l.selectedStyle.Foreground(fg))
+			}
+		}
+		y++
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (l *MonthlyList) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		step := 0
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyDown, tcell.KeyRight:
+			step = 1
+		case tcell.KeyBacktab, tcell.KeyUp, tcell.KeyLeft:
+			step = -1
+		case tcell.KeyHome:
+			l.currentItem = 0
+		case tcell.KeyEnd:
+			l.currentItem = len(l.rows) - 1
+		case tcell.KeyPgDn:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem += height
+			if l.currentItem >= len(l.rows) {
+				l.currentItem = len(l.rows) - 1
+			}
+		case tcell.KeyPgUp:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem -= height
+			if l.currentItem < 0 {
+				l.currentItem = 0
+			}
+		}
+		if step != 0 {
+			l.currentItem += step
+			// The header of the tasks can't be selected.
+			if l.currentItem >= 0 && l.currentItem < len(l.rows) && l.rows[l.currentItem].header {
+				l.currentItem += step
+			}
+		}
+		if l.currentItem < 0 || l.currentItem >= len(l.rows) {
+			l.currentItem = -1
+		}
+	})
+}
@@ -132,6 +132,22 @@ func (p *Prompt) keyboard(evt *tcell.EventKey) *tcell.EventKey {
 	case tcell.KeyEscape:
 		p.model.ClearText(true)
 		p.model.SetActive(false)
+	case tcell.KeyTab:
+		// Accept the suggestion.
+		if s, ok := p.model.(Suggester); ok {
+			if suggestion, ok := s.CurrentSuggestion(); ok {
+				p.model.SetText(p.model.GetText() + suggestion)
+			}
+		}
+	case tcell.KeyDown, tcell.KeyUp:
+		if s, ok := p.model.(Suggester); ok {
+			if evt.Key() == tcell.KeyDown {
+				s.NextSuggestion()
+			} else {
+				s.PrevSuggestion()
+			}
+			p.update(p.model.GetText())
+		}
 	case tcell.KeyEnter, tcell.KeyCtrlE:
 		p.model.SetText(p.model.GetText())
 		p.model.SetActive(false)
@@ -164,13 +180,21 @@ func (p *Prompt) activate() {
 }
 
 func (p *Prompt) update(text string) {
+	if s, ok := p.model.(Suggester); ok {
+		if suggestion, ok := s.CurrentSuggestion(); ok {
+			p.suggest(text, suggestion)
+			return
+		}
+	}
 	p.Clear()
 	p.write(text)
 }
 
+// suggest writes the text followed by the dimmed suggestion, that Tab accepts.
 func (p *Prompt) suggest(text, suggestion string) {
 	p.Clear()
 	p.write(text)
+	fmt.Fprintf(p, "[gray::-]%s", suggestion)
 }
 
 func (p *Prompt) write(text string) {
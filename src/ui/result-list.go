@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/derailed/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ResultList displays the results of a search with the day of each log.
+type ResultList struct {
+	*tview.Box
+
+	results []search.Result
+
+	// The index of the currently selected result.
+	currentItem int
+
+	// The item main text style.
+	mainTextStyle tcell.Style
+
+	// The style of the dates.
+	dateStyle tcell.Style
+
+	// The style for selected items.
+	selectedStyle tcell.Style
+
+	// The number of results skipped at the top before the first one is drawn.
+	itemOffset int
+
+	// An optional function which is called when a result is selected with
+	// Enter.
+	selected func(result search.Result)
+}
+
+// NewResultList returns a new result list.
+func NewResultList() *ResultList {
+	return &ResultList{
+		Box:           tview.NewBox(),
+		currentItem:   -1,
+		mainTextStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor),
+		dateStyle:     tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
+		selectedStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimitiveBackgroundColor).
+			Background(tview.Styles.PrimaryTextColor),
+	}
+}
+
+// SetResults sets the results shown by the list.
+func (l *ResultList) SetResults(results []search.Result) *ResultList {
+	l.results = results
+	if l.currentItem >= len(results) {
+		l.currentItem = len(results) - 1
+	}
+	return l
+}
+
+// GetResults returns the results shown by the list.
+func (l *ResultList) GetResults() []search.Result {
+	return l.results
+}
+
+// SetCurrentItem selects the result with the index.
+func (l *ResultList) SetCurrentItem(index int) *ResultList {
+	if index >= len(l.results) {
+		index = len(l.results) - 1
+	}
+	l.currentItem = index
+	return l
+}
+
+// GetCurrentItem returns the index of the selected result, -1 if none.
+func (l *ResultList) GetCurrentItem() int {
+	return l.currentItem
+}
+
+// SetSelectedFunc sets the function called when a result is selected.
+func (l *ResultList) SetSelectedFunc(handler func(result search.Result)) *ResultList {
+	l.selected = handler
+	return l
+}
+
+// Draw draws this primitive onto the screen.
+func (l *ResultList) Draw(screen tcell.Screen) {
+	l.Box.DrawForSubclass(screen, l)
+
+	x, y, width, height := l.GetInnerRect()
+	bottomLimit := y + height
+	_, totalHeight := screen.Size()
+	if bottomLimit > totalHeight {
+		bottomLimit = totalHeight
+	}
+
+	// Adjust offset to keep the current selection in view.
+	if l.currentItem >= 0 && l.currentItem < l.itemOffset {
+		l.itemOffset = l.currentItem
+	} else if l.currentItem-l.itemOffset >= height {
+		l.itemOffset = l.currentItem + 1 - height
+	}
+
+	if len(l.results) == 0 {
+		printWithStyle(screen, "No results", x+1, y, 0, width-1, AlignLeft, l.dateStyle, true)
+		return
+	}
+	for index, result := range l.results {
+		if index < l.itemOffset {
+			continue
+		}
+		if y >= bottomLimit {
+			break
+		}
+		date := result.Date.Format("02.01.2006")
+		printWithStyle(screen, date, x+1, y, 0, width-1, AlignLeft, l.dateStyle, true)
+		if result.Log.Important {
+			printWithStyle(screen, importantSignifier, x+11, y, 0, 1, AlignLeft, importantStyle, true)
+		}
+		printWithStyle(screen, fmt.Sprintf("(%s)", string(result.Log.Mark.Print())), x+12, y, 0, 3, AlignLeft, result.Log.Mark.Style(), true)
+		printWithStyle(screen, highlightTags(result.Log.Name), x+16, y, 0, width-16, AlignLeft, l.mainTextStyle, true)
+
+		if index == l.currentItem {
+			for bx := 0; bx < width; bx++ {
+				m, c, style, _ := screen.GetContent(x+bx, y)
+				fg, _, _ := style.Decompose()
+				screen.SetContent(x+bx, y, m, c, l.selectedStyle.Foreground(fg))
+			}
+		}
+		y++
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (l *ResultList) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyDown, tcell.KeyRight:
+			l.currentItem++
+		case tcell.KeyBacktab, tcell.KeyUp, tcell.KeyLeft:
+			l.currentItem--
+		case tcell.KeyHome:
+			l.currentItem = 0
+		case tcell.KeyEnd:
+			l.currentItem = len(l.results) - 1
+		case tcell.KeyPgDn:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem += height
+			if l.currentItem >= len(l.results) {
+				l.currentItem = len(l.results) - 1
+			}
+		case tcell.KeyPgUp:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem -= height
+			if l.currentItem < 0 {
+				l.currentItem = 0
+			}
+		case tcell.KeyEnter:
+			if l.currentItem >= 0 && l.currentItem < len(l.results) && l.selected != nil {
+				l.selected(l.results[l.currentItem])
+			}
+		}
+		if l.currentItem < 0 || l.currentItem >= len(l.results) {
+			l.currentItem = -1
+		}
+	})
+}
@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apoloa/bjournal/src/search"
+	"github.com/apoloa/bjournal/src/utils"
+	"github.com/derailed/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// reviewRow is a line of the review: the header of a day or one of its tasks.
+type reviewRow struct {
+	date time.Time
+	// task is the index of the result, -1 for the headers.
+	task int
+}
+
+// ReviewList displays the open tasks of the past days grouped by day, with
+// the age of each day, and lets select several of them.
+type ReviewList struct {
+	*tview.Box
+
+	results []search.Result
+
+	// The rows drawn, the headers of the days followed by their tasks.
+	rows []reviewRow
+
+	// The ids of the selected tasks.
+	checked map[string]bool
+
+	// The index of the currently selected row.
+	currentItem int
+
+	// The day the age is counted from.
+	today time.Time
+
+	// The item main text style.
+	mainTextStyle tcell.Style
+
+	// The style of the headers of the days.
+	dayStyle tcell.Style
+
+	// The style of the headers of the days older than a week.
+	oldStyle tcell.Style
+
+	// The style of the headers of the days older than a month.
+	staleStyle tcell.Style
+
+	// The style for selected items.
+	selectedStyle tcell.Style
+
+	// The number of rows skipped at the top before the first row is drawn.
+	itemOffset int
+}
+
+// NewReviewList returns a new review list.
+func NewReviewList(today time.Time) *ReviewList {
+	return &ReviewList{
+		Box:           tview.NewBox(),
+		checked:       map[string]bool{},
+		currentItem:   -1,
+		today:         time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC),
+		mainTextStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor),
+		dayStyle:      tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
+		oldStyle:      tcell.StyleDefault.Foreground(tcell.ColorYellow),
+		staleStyle:    tcell.StyleDefault.Foreground(tcell.ColorOrangeRed),
+		selectedStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimitiveBackgroundColor).
+			Background(tview.Styles.PrimaryTextColor),
+	}
+}
+
+// SetTasks sets the open tasks, sorted by day, keeping the selection of the
+// tasks that are still open. The selected row stays on the same task, or on
+// the same position when the task is gone.
+func (l *ReviewList) SetTasks(results []search.Result) *ReviewList {
+	current := l.currentLogId()
+	previousItem := l.currentItem
+	l.results = results
+	l.rows = nil
+	ids := map[string]bool{}
+	for i, result := range results {
+		ids[result.Log.Id] = true
+		if i == 0 || !results[i-1].Date.Equal(result.Date) {
+			l.rows = append(l.rows, reviewRow{date: result.Date, task: -1})
+		}
+		l.rows = append(l.rows, reviewRow{date: result.Date, task: i})
+	}
+	for id := range l.checked {
+		if !ids[id] {
+			delete(l.checked, id)
+		}
+	}
+	l.SetCurrentItem(previousItem)
+	for i, row := range l.rows {
+		if current != "" && row.task >= 0 && results[row.task].Log.Id == current {
+			l.currentItem = i
+		}
+	}
+	return l
+}
+
+// GetResults returns the open tasks.
+func (l *ReviewList) GetResults() []search.Result {
+	return l.results
+}
+
+// SetCurrentItem selects the row with the index.
+func (l *ReviewList) SetCurrentItem(index int) *ReviewList {
+	if index >= len(l.rows) {
+		index = len(l.rows) - 1
+	}
+	l.currentItem = index
+	return l
+}
+
+// GetCurrentItem returns the index of the selected row, -1 if none.
+func (l *ReviewList) GetCurrentItem() int {
+	return l.currentItem
+}
+
+func (l *ReviewList) currentLogId() string {
+	if l.currentItem < 0 || l.currentItem >= len(l.rows) || l.rows[l.currentItem].task < 0 {
+		return ""
+	}
+	return l.results[l.rows[l.currentItem].task].Log.Id
+}
+
+// GetSelectedIds returns the ids of the checked tasks in the order of the
+// list, or the task of the selected row when none is checked.
+func (l *ReviewList) GetSelectedIds() []string {
+	var ids []string
+	for _, result := range l.results {
+		if l.checked[result.Log.Id] {
+			ids = append(ids, result.Log.Id)
+		}
+	}
+	if len(ids) == 0 {
+		if id := l.currentLogId(); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// toggle checks or unchecks the task of the selected row, or every task of
+// the day when the row is a header.
+func (l *ReviewList) toggle() {
+	if l.currentItem < 0 || l.currentItem >= len(l.rows) {
+		return
+	}
+	row := l.rows[l.currentItem]
+	if row.task >= 0 {
+		id := l.results[row.task].Log.Id
+		l.checked[id] = !l.checked[id]
+		return
+	}
+	var ids []string
+	allChecked := true
+	for _, result := range l.results {
+		if result.Date.Equal(row.date) {
+			ids = append(ids, result.Log.Id)
+			allChecked = allChecked && l.checked[result.Log.Id]
+		}
+	}
+	for _, id := range ids {
+		l.checked[id] = !allChecked
+	}
+}
+
+// toggleAll checks every task, or unchecks them when all were checked.
+func (l *ReviewList) toggleAll() {
+	allChecked := len(l.results) > 0
+	for _, result := range l.results {
+		allChecked = allChecked && l.checked[result.Log.Id]
+	}
+	for _, result := range l.results {
+		l.checked[result.Log.Id] = !allChecked
+	}
+}
+
+// age returns the text and style of the header of the day.
+func (l *ReviewList) age(date time.Time) (string, tcell.Style) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(l.today.Sub(day).Hours() / 24)
+	switch {
+	case days == 1:
+		return "yesterday", l.dayStyle
+	case days > 30:
+		return fmt.Sprintf("%d days ago", days), l.staleStyle
+	case days > 7:
+		return fmt.Sprintf("%d days ago", days), l.oldStyle
+	}
+	return fmt.Sprintf("%d days ago", days), l.dayStyle
+}
+
+// Draw draws this primitive onto the screen.
+func (l *ReviewList) Draw(screen tcell.Screen) {
+	l.Box.DrawForSubclass(screen, l)
+
+	x, y, width, height := l.GetInnerRect()
+	bottomLimit := y + height
+	_, totalHeight := screen.Size()
+	if bottomLimit > totalHeight {
+		bottomLimit = totalHeight
+	}
+
+	// Adjust offset to keep the current selection in view.
+	if l.currentItem >= 0 && l.currentItem < l.itemOffset {
+		l.itemOffset = l.currentItem
+	} else if l.currentItem-l.itemOffset >= height {
+		l.itemOffset = l.currentItem + 1 - height
+	}
+
+	if len(l.rows) == 0 {
+		printWithStyle(screen, "No open tasks", x+1, y, 0, width-1, AlignLeft, l.dayStyle, true)
+		return
+	}
+	for index, row := range l.rows {
+		if index < l.itemOffset {
+			continue
+		}
+		if y >= bottomLimit {
+			break
+		}
+		if row.task < 0 {
+			age, style := l.age(row.date)
+			title := fmt.Sprintf("%02d.%02d.%d %v · %v", row.date.Day(), row.date.Month(), row.date.Year(), utils.ToShortString(row.date.Weekday()), age)
+			printWithStyle(screen, title, x+1, y, 0, width-1, AlignLeft, style.Bold(true), true)
+		} else {
+			log := l.results[row.task].Log
+			check := "[ ]"
+			if l.checked[log.Id] {
+				check = "[x]"
+			}
+			printWithStyle(screen, check, x+2, y, 0, 3, AlignLeft, l.mainTextStyle, true)
+			if log.Important {
+				printWithStyle(screen, importantSignifier, x+5, y, 0, 1, AlignLeft, importantStyle, true)
+			}
+			printWithStyle(screen, fmt.Sprintf("(%s)", string(log.Mark.Print())), x+6, y, 0, 3, AlignLeft, log.Mark.Style(), true)
+			printWithStyle(screen, log.Name, x+10, y, 0, width-10, AlignLeft, l.mainTextStyle, true)
+		}
+
+		if index == l.currentItem {
+			for bx := 0; bx < width; bx++ {
+				m, c, style, _ := screen.GetContent(x+bx, y)
+				fg, _, _ := style.Decompose()
+				screen.SetContent(x+bx, y, m, c, l.selectedStyle.Foreground(fg))
+			}
+		}
+		y++
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (l *ReviewList) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyDown, tcell.KeyRight:
+			l.currentItem++
+		case tcell.KeyBacktab, tcell.KeyUp, tcell.KeyLeft:
+			l.currentItem--
+		case tcell.KeyHome:
+			l.currentItem = 0
+		case tcell.KeyEnd:
+			l.currentItem = len(l.rows) - 1
+		case tcell.KeyPgDn:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem += height
+			if l.currentItem >= len(l.rows) {
+				l.currentItem = len(l.rows) - 1
+			}
+		case tcell.KeyPgUp:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem -= height
+			if l.currentItem < 0 {
+				l.currentItem = 0
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case ' ':
+				l.toggle()
+			case 'a':
+				l.toggleAll()
+			}
+		}
+		if l.currentItem < 0 || l.currentItem >= len(l.rows) {
+			l.currentItem = -1
+		}
+	})
+}
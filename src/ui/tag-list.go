@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	model2 "github.com/apoloa/bjournal/src/model"
+	"github.com/derailed/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	// tagColor and contextColor are the colors of the #tags and @contexts in
+	// the text of the logs.
+	tagColor     = "dodgerblue"
+	contextColor = "mediumorchid"
+)
+
+// highlightTags escapes the text and colors its #tags and @contexts.
+func highlightTags(text string) string {
+	var highlighted strings.Builder
+	var word []rune
+	flush := func() {
+		tags, contexts := model2.ParseTags(string(word))
+		switch {
+		case len(tags) > 0 && word[0] == model2.TagPrefix:
+			fmt.Fprintf(&highlighted, "[%s]%s[-]", tagColor, string(word))
+		case len(contexts) > 0 && word[0] == model2.ContextPrefix:
+			fmt.Fprintf(&highlighted, "[%s]%s[-]", contextColor, string(word))
+		default:
+			highlighted.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range Escape(text) {
+		if model2.IsTagSeparator(r) {
+			flush()
+			highlighted.WriteRune(r)
+		} else {
+			word = append(word, r)
+		}
+	}
+	flush()
+	return highlighted.String()
+}
+
+// TagList displays the tags and contexts of the journal with the number of
+// logs that have each of them.
+type TagList struct {
+	*tview.Box
+
+	tags []model2.TagCount
+
+	// The index of the currently selected tag.
+	currentItem int
+
+	// The style of the tags.
+	tagStyle tcell.Style
+
+	// The style of the contexts.
+	contextStyle tcell.Style
+
+	// The style of the counts.
+	countStyle tcell.Style
+
+	// The style for selected items.
+	selectedStyle tcell.Style
+
+	// The number of tags skipped at the top before the first one is drawn.
+	itemOffset int
+}
+
+// NewTagList returns a new tag list.
+func NewTagList() *TagList {
+	return &TagList{
+		Box:          tview.NewBox(),
+		currentItem:  -1,
+		tagStyle:     tcell.StyleDefault.Foreground(tcell.GetColor(tagColor)),
+		contextStyle: tcell.StyleDefault.Foreground(tcell.GetColor(contextColor)),
+		countStyle:   tcell.StyleDefault.Foreground(tview.Styles.TertiaryTextColor),
+		selectedStyle: tcell.StyleDefault.Foreground(tview.Styles.PrimitiveBackgroundColor).
+			Background(tview.Styles.PrimaryTextColor),
+	}
+}
+
+// SetTags sets the tags, keeping the selected tag when it is still there and
+// selecting the first one when none was.
+func (l *TagList) SetTags(tags []model2.TagCount) *TagList {
+	current, ok := l.GetCurrentTag()
+	l.tags = tags
+	if l.currentItem >= len(tags) || l.currentItem < 0 {
+		l.currentItem = 0
+	}
+	if len(tags) == 0 {
+		l.currentItem = -1
+	}
+	for i, tag := range tags {
+		if ok && tag.Tag == current.Tag {
+			l.currentItem = i
+		}
+	}
+	return l
+}
+
+// GetCurrentTag returns the selected tag, false if there is none.
+func (l *TagList) GetCurrentTag() (model2.TagCount, bool) {
+	if l.currentItem < 0 || l.currentItem >= len(l.tags) {
+		return model2.TagCount{}, false
+	}
+	return l.tags[l.currentItem], true
+}
+
+// Draw draws this primitive onto the screen.
+func (l *TagList) Draw(screen tcell.Screen) {
+	l.Box.DrawForSubclass(screen, l)
+
+	x, y, width, height := l.GetInnerRect()
+	bottomLimit := y + height
+	_, totalHeight := screen.Size()
+	if bottomLimit > totalHeight {
+		bottomLimit = totalHeight
+	}
+
+	// Adjust offset to keep the current selection in view.
+	if l.currentItem >= 0 && l.currentItem < l.itemOffset {
+		l.itemOffset = l.currentItem
+	} else if l.currentItem-l.itemOffset >= height {
+		l.itemOffset = l.currentItem + 1 - height
+	}
+
+	if len(l.tags) == 0 {
+		printWithStyle(screen, "No tags", x+1, y, 0, width-1, AlignLeft, l.countStyle, true)
+		return
+	}
+	for index, tag := range l.tags {
+		if index < l.itemOffset {
+			continue
+		}
+		if y >= bottomLimit {
+			break
+		}
+		style := l.tagStyle
+		if strings.HasPrefix(tag.Tag, string(model2.ContextPrefix)) {
+			style = l.contextStyle
+		}
+		count := fmt.Sprint(tag.Count)
+		printWithStyle(screen, Escape(tag.Tag), x+1, y, 0, width-len(count)-2, AlignLeft, style, true)
+		printWithStyle(screen, count, x, y, 0, width-1, AlignRight, l.countStyle, true)
+
+		if index == l.currentItem {
+			for bx := 0; bx < width; bx++ {
+				m, c, style, _ := screen.GetContent(x+bx, y)
+				fg, _, _ := style.Decompose()
+				screen.SetContent(x+bx, y, m, c, l.selectedStyle.Foreground(fg))
+			}
+		}
+		y++
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (l *TagList) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if len(l.tags) == 0 {
+			return
+		}
+		switch event.Key() {
+		case tcell.KeyDown:
+			l.currentItem++
+		case tcell.KeyUp:
+			l.currentItem--
+		case tcell.KeyHome:
+			l.currentItem = 0
+		case tcell.KeyEnd:
+			l.currentItem = len(l.tags) - 1
+		case tcell.KeyPgDn:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem += height
+		case tcell.KeyPgUp:
+			_, _, _, height := l.GetInnerRect()
+			l.currentItem -= height
+		}
+		// The list always keeps a tag selected.
+		if l.currentItem < 0 {
+			l.currentItem = 0
+		} else if l.currentItem >= len(l.tags) {
+			l.currentItem = len(l.tags) - 1
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"github.com/derailed/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// WeekFlex lays out its items in columns, or stacks them in rows when the
+// columns would be narrower than the minimum width.
+type WeekFlex struct {
+	*tview.Flex
+
+	minColumnWidth int
+
+	columns int
+}
+
+// NewWeekFlex returns a new flex for columns of at least the given width.
+func NewWeekFlex(minColumnWidth int) *WeekFlex {
+	return &WeekFlex{
+		Flex:           tview.NewFlex(),
+		minColumnWidth: minColumnWidth,
+	}
+}
+
+// AddColumn adds the primitive as a column of the same size as the others.
+func (f *WeekFlex) AddColumn(p tview.Primitive) *WeekFlex {
+	f.AddItem(p, 0, 1, false)
+	f.columns++
+	return f
+}
+
+// Draw draws this primitive onto the screen.
+func (f *WeekFlex) Draw(screen tcell.Screen) {
+	_, _, width, _ := f.GetRect()
+	if f.columns > 0 && width/f.columns < f.minColumnWidth {
+		f.SetDirection(tview.FlexRow)
+	} else {
+		f.SetDirection(tview.FlexColumn)
+	}
+	f.Flex.Draw(screen)
+}
@@ -0,0 +1,101 @@
+package timeconv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ParseDate parses a date written by the user relative to now. It accepts
+// YYYY-MM-DD, DD.MM.YYYY, today, yesterday, tomorrow, a weekday optionally
+// preceded by last or next, "N days ago" and "+N"/"-N" days. A weekday alone
+// is the last one, today included.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	text := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	switch text {
+	case "", "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	if date, err := time.ParseInLocation("2006-01-02", text, now.Location()); err == nil {
+		return date, nil
+	}
+	if date, err := time.ParseInLocation(dayLayout, text, now.Location()); err == nil {
+		return date, nil
+	}
+	if strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+		if days, err := strconv.Atoi(text); err == nil {
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+	if strings.HasSuffix(text, " ago") {
+		fields := strings.Fields(text)
+		if len(fields) == 3 && (fields[1] == "days" || fields[1] == "day") {
+			if days, err := strconv.Atoi(fields[0]); err == nil {
+				return today.AddDate(0, 0, -days), nil
+			}
+		}
+	}
+	fields := strings.Fields(text)
+	if weekday, ok := weekdays[fields[len(fields)-1]]; ok && len(fields) <= 2 {
+		switch {
+		case len(fields) == 1:
+			return today.AddDate(0, 0, -daysSince(today.Weekday(), weekday)), nil
+		case fields[0] == "last":
+			days := daysSince(today.Weekday(), weekday)
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, -days), nil
+		case fields[0] == "next":
+			days := daysSince(weekday, today.Weekday())
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD, yesterday, last friday...", value)
+}
+
+// ParseMonthOrDate parses a month as YYYY-MM, returning true, or any of the
+// dates accepted by ParseDate.
+func ParseMonthOrDate(value string, now time.Time) (time.Time, bool, error) {
+	if month, err := time.ParseInLocation("2006-01", strings.TrimSpace(value), now.Location()); err == nil {
+		return month, true, nil
+	}
+	date, err := ParseDate(value, now)
+	return date, false, err
+}
+
+// daysSince returns the days from the last weekday to the day, from 0 to 6.
+func daysSince(day, weekday time.Weekday) int {
+	return (int(day) - int(weekday) + 7) % 7
+}
+
+// ParseWeekday parses the English name of a weekday, in full or its first
+// three letters, ignoring the case.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for full, weekday := range weekdays {
+		if name == full || (len(name) == 3 && strings.HasPrefix(full, name)) {
+			return weekday, true
+		}
+	}
+	return time.Sunday, false
+}
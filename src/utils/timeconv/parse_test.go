@@ -0,0 +1,56 @@
+package timeconv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDate(t *testing.T) {
+	// A Saturday.
+	now := time.Date(2026, time.October, 17, 15, 30, 0, 0, time.UTC)
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	cases := map[string]time.Time{
+		"":               day(time.October, 17),
+		"today":          day(time.October, 17),
+		"yesterday":      day(time.October, 16),
+		"Tomorrow":       day(time.October, 18),
+		"2026-10-01":     day(time.October, 1),
+		"01.10.2026":     day(time.October, 1),
+		"friday":         day(time.October, 16),
+		"saturday":       day(time.October, 17),
+		"last friday":    day(time.October, 16),
+		"last  saturday": day(time.October, 10),
+		"next monday":    day(time.October, 19),
+		"next saturday":  day(time.October, 24),
+		"3 days ago":     day(time.October, 14),
+		"-1":             day(time.October, 16),
+		"+2":             day(time.October, 19),
+	}
+	for text, expected := range cases {
+		date, err := ParseDate(text, now)
+		assert.Nil(t, err, text)
+		assert.Equal(t, expected, date, text)
+	}
+
+	for _, text := range []string{"someday", "last", "2026-13-01", "friday next"} {
+		_, err := ParseDate(text, now)
+		assert.NotNil(t, err, text)
+	}
+}
+
+func TestParseMonthOrDate(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 15, 30, 0, 0, time.UTC)
+	date, month, err := ParseMonthOrDate("2027-01", now)
+	assert.Nil(t, err)
+	assert.True(t, month)
+	assert.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), date)
+
+	date, month, err = ParseMonthOrDate("next friday", now)
+	assert.Nil(t, err)
+	assert.False(t, month)
+	assert.Equal(t, time.Date(2026, time.October, 23, 0, 0, 0, 0, time.UTC), date)
+}
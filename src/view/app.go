@@ -1,17 +1,22 @@
 package view
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/gdamore/tcell/v2"
 
 	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/search"
 	"github.com/apoloa/bjournal/src/service"
 	"github.com/apoloa/bjournal/src/ui"
 	"github.com/apoloa/bjournal/src/utils"
+	"github.com/apoloa/bjournal/src/utils/timeconv"
 	"github.com/derailed/tview"
 	zerolog "github.com/rs/zerolog/log"
 )
@@ -22,22 +27,81 @@ const (
 	Today SelectedView = iota
 	PreviousDate
 	Index
+	Monthly
+	Search
+	Review
+	History
+	Important
+	Tags
+	Habits
+)
+
+// promptMode is what the text of the prompt is used for.
+type promptMode int
+
+const (
+	// promptLog adds a log of the selected category.
+	promptLog promptMode = iota
+	// promptDate shows the day of the date.
+	promptDate
+	// promptSearch shows the results of the query.
+	promptSearch
+	// promptSchedule schedules the selected tasks for the month or date.
+	promptSchedule
+	// promptEdit renames the selected log.
+	promptEdit
 )
 
 type App struct {
-	logService       *service.LogService
-	prompt           *ui.Prompt
-	buffer           *model.CmdBuff
-	app              *tview.Application
-	mainFlex         *tview.Flex
-	dailyList        *ui.List
-	previousDayList  *ui.List
-	indexList        *ui.IndexList
-	showingPrompt    bool
-	showPreviousDay  bool
-	showIndex        bool
+	logService      *service.LogService
+	prompt          *ui.Prompt
+	buffer          *model.CmdBuff
+	app             *tview.Application
+	mainFlex        *tview.Flex
+	status          *tview.TextView
+	statusMessage   string
+	dailyList       *ui.List
+	previousDayList *ui.List
+	indexList       *ui.IndexList
+	monthlyList     *ui.MonthlyList
+	index           model.Index
+	showingPrompt   bool
+	promptMode      promptMode
+	resultList      *ui.ResultList
+	searchQuery     string
+	reviewList      *ui.ReviewList
+	historyList     *ui.ResultList
+	importantList   *ui.ResultList
+	tagList         *ui.TagList
+	taggedList      *ui.ResultList
+	// taggedFocus is true when the keys of the tag browser go to the logs of
+	// the tag instead of the tags.
+	taggedFocus bool
+	habitGrid   *ui.HabitGrid
+	// importantMode is how the days order the important logs.
+	importantMode ui.ImportantMode
+	// historyId is the log whose migrations are shown in the history.
+	historyId string
+	// migrationWarning is the number of migrations after which a log is
+	// highlighted, 0 disables it.
+	migrationWarning int
+	// panel is the view shown next to the day, Today when there is none.
+	panel            SelectedView
+	showWeek         bool
 	selectedView     SelectedView
 	selectedCategory *model.Category
+	// date is the displayed day, where the logs are added.
+	date time.Time
+	// editId is the log renamed by the prompt.
+	editId string
+	// tags are the tags suggested by the prompt, read when it opens.
+	tags []model.TagCount
+	// confirmed is run when the question of the status line is answered with
+	// y, any other key cancels it.
+	confirmed func()
+	// collapsed are the ids of the logs whose sub logs are hidden, kept when
+	// the lists are rebuilt.
+	collapsed map[string]bool
 }
 
 func NewApp(logService *service.LogService) *App {
@@ -52,17 +116,54 @@ func NewApp(logService *service.LogService) *App {
 		buffer:     buffer,
 		app:        tview.NewApplication(),
 		mainFlex:   mainFlex,
+		date:       time.Now(),
+		collapsed:  map[string]bool{},
 	}
 	buffer.AddListener(app)
+	buffer.SetSuggestionFn(app.suggestTags)
 	return app
 }
 
+// SetMigrationWarning highlights the logs migrated more than the given times.
+func (a *App) SetMigrationWarning(migrations int) *App {
+	a.migrationWarning = migrations
+	return a
+}
+
+// newList returns a list for the logs of a day.
+func (a *App) newList() *ui.List {
+	return ui.NewList().SetMigrationWarning(a.migrationWarning).SetCollapsed(a.collapsed).SetImportantMode(a.importantMode)
+}
+
+// selectedLogId returns the id of the selected log of the list.
+func selectedLogId(list *ui.List) string {
+	if list == nil {
+		return ""
+	}
+	if log := list.GetCurrentLog(); log != nil {
+		return log.Id
+	}
+	return ""
+}
+
 func (a *App) buildPreviousDay(timeNow time.Time) {
-	previousDate, _ := a.logService.GetPreviousDate(time.Now())
-	previousList := ui.NewList().AddDailyLog(&previousDate)
-	previousList.
-		SetBorder(true).
-		SetTitle(fmt.Sprintf("%02d.%02d %v", previousDate.Date.Day(), previousDate.Date.Month(), utils.ToShortString(timeNow.Weekday())))
+	previousList := a.newList()
+	previousList.SetBorder(true)
+	previousDate, err := a.logService.GetPreviousDate(timeNow)
+	if err != nil {
+		// Without a previous day the panel is empty, there is nothing to
+		// migrate from.
+		if !errors.Is(err, service.ErrNoPreviousDay) {
+			zerolog.Print("Error reading the previous day ", err)
+		}
+		previousList.SetTitle("No previous day")
+	} else {
+		previousList.AddDailyLog(&previousDate)
+		if id := selectedLogId(a.previousDayList); id != "" {
+			previousList.SelectLog(id)
+		}
+		previousList.SetTitle(fmt.Sprintf("%02d.%02d %v", previousDate.Date.Day(), previousDate.Date.Month(), utils.ToShortString(previousDate.Date.Weekday())))
+	}
 	if a.selectedView == PreviousDate {
 		previousList.SetBorderColor(tcell.ColorBlue)
 	} else {
@@ -71,15 +172,40 @@ func (a *App) buildPreviousDay(timeNow time.Time) {
 	a.previousDayList = previousList
 }
 
+func (a *App) buildMonthly(timeNow time.Time) {
+	monthly, err := a.logService.ReadMonth(timeNow)
+	if err != nil {
+		zerolog.Print("Error reading month ", err)
+	}
+	monthlyList := ui.NewMonthlyList().AddMonthlyLog(&monthly)
+	if a.monthlyList != nil {
+		if log := a.monthlyList.GetCurrentLog(); log != nil {
+			monthlyList.SelectLog(log.Id)
+		} else if day := a.monthlyList.GetCurrentDay(); day != 0 {
+			monthlyList.SelectDay(day)
+		}
+	}
+	monthlyList.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf("%v %d", monthly.Date.Month(), monthly.Date.Year()))
+	if a.selectedView == Monthly {
+		monthlyList.SetBorderColor(tcell.ColorBlue)
+	} else {
+		monthlyList.SetBorderColor(tcell.ColorWhite)
+	}
+	a.monthlyList = monthlyList
+}
+
 func (a *App) makeDayFlex(fetchFromCache bool) *tview.Flex {
 	flex := tview.NewFlex()
-	timeNow := time.Now()
-	if a.showPreviousDay {
+	timeNow := a.date
+	if a.panel == PreviousDate {
 		a.buildPreviousDay(timeNow)
 		flex.AddItem(a.previousDayList, 0, 1, false)
 	}
-	if a.showIndex {
-		indexList := ui.NewIndexList().AddIndexModel(&a.logService.Index)
+	if a.panel == Index {
+		a.index = a.logService.GetIndex()
+		indexList := ui.NewIndexList().AddIndexModel(&a.index)
 		indexList.
 			SetBorder(true).
 			SetTitle("Index")
@@ -91,10 +217,40 @@ func (a *App) makeDayFlex(fetchFromCache bool) *tview.Flex {
 		a.indexList = indexList
 		flex.AddItem(indexList, 0, 1, false)
 	}
+	if a.panel == Monthly {
+		a.buildMonthly(timeNow)
+		flex.AddItem(a.monthlyList, 0, 1, false)
+	}
+	if a.panel == Search {
+		a.buildSearch(fetchFromCache)
+		flex.AddItem(a.resultList, 0, 1, false)
+	}
+	if a.panel == Review {
+		a.buildReview(fetchFromCache)
+		flex.AddItem(a.reviewList, 0, 1, false)
+	}
+	if a.panel == History {
+		a.buildHistory()
+		flex.AddItem(a.historyList, 0, 1, false)
+	}
+	if a.panel == Important {
+		a.buildImportant()
+		flex.AddItem(a.importantList, 0, 1, false)
+	}
+	if a.panel == Tags {
+		flex.AddItem(a.buildTags(fetchFromCache), 0, 1, false)
+	}
+	if a.panel == Habits {
+		a.buildHabits(fetchFromCache)
+		flex.AddItem(a.habitGrid, 0, 1, false)
+	}
 	if fetchFromCache {
 		dl, _ := a.logService.ReadDay(timeNow)
-		list := ui.NewList().
+		list := a.newList().
 			AddDailyLog(&dl)
+		if id := selectedLogId(a.dailyList); id != "" {
+			list.SelectLog(id)
+		}
 		list.
 			SetBorder(true).
 			SetTitle(fmt.Sprintf("%02d.%02d %v", dl.Date.Day(), dl.Date.Month(), utils.ToShortString(dl.Date.Weekday())))
@@ -109,22 +265,397 @@ func (a *App) makeDayFlex(fetchFromCache bool) *tview.Flex {
 	return flex
 }
 
+// minDayWidth is the minimum width of the columns of the week, narrower
+// terminals show the days stacked.
+const minDayWidth = 24
+
+// makeWeekFlex shows the seven days of the ISO week of the displayed day, the
+// displayed day is the focused column.
+func (a *App) makeWeekFlex() *ui.WeekFlex {
+	flex := ui.NewWeekFlex(minDayWidth)
+	today := timeconv.TimeToDayString(time.Now())
+	monday := a.date.AddDate(0, 0, -(int(a.date.Weekday())+6)%7)
+	for i := 0; i < 7; i++ {
+		date := monday.AddDate(0, 0, i)
+		dl, err := a.logService.ReadDay(date)
+		if err != nil {
+			zerolog.Print("Error reading day ", err)
+		}
+		list := a.newList().AddDailyLog(&dl)
+		list.
+			SetBorder(true).
+			SetTitle(fmt.Sprintf("%v %02d.%02d", utils.ToShortString(date.Weekday()), date.Day(), date.Month()))
+		if timeconv.TimeToDayString(date) == today {
+			list.SetTitleColor(tcell.ColorYellow)
+		}
+		if timeconv.TimeToDayString(date) == timeconv.TimeToDayString(a.date) {
+			if id := selectedLogId(a.dailyList); id != "" {
+				list.SelectLog(id)
+			}
+			list.SetBorderColor(tcell.ColorBlue)
+			a.dailyList = list
+		} else {
+			list.SetBorderColor(tcell.ColorWhite)
+		}
+		flex.AddColumn(list)
+	}
+	return flex
+}
+
+// togglePanel shows the panel next to the day with the focus on it, or hides
+// it when it was already shown.
+func (a *App) togglePanel(panel SelectedView) {
+	a.showWeek = false
+	if a.panel == panel {
+		a.panel = Today
+	} else {
+		a.panel = panel
+	}
+	a.selectedView = a.panel
+	a.rebuild(true)
+}
+
+func (a *App) buildSearch(fetchFromCache bool) {
+	resultList := ui.NewResultList()
+	if fetchFromCache || a.resultList == nil {
+		var results []search.Result
+		query, err := search.Parse(a.searchQuery, time.Now())
+		if err == nil {
+			results, err = a.logService.Search(query)
+		}
+		if err != nil {
+			zerolog.Print("Error searching ", err)
+		}
+		resultList.SetResults(results)
+	} else {
+		resultList.SetResults(a.resultList.GetResults())
+	}
+	if a.resultList != nil {
+		resultList.SetCurrentItem(a.resultList.GetCurrentItem())
+	}
+	resultList.SetSelectedFunc(func(result search.Result) {
+		a.showDate(result.Date)
+		a.dailyList.SelectLog(result.Log.Id)
+		a.selectedView = Today
+		a.rebuild(false)
+	})
+	resultList.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf("Search: %v", a.searchQuery))
+	if a.selectedView == Search {
+		resultList.SetBorderColor(tcell.ColorBlue)
+	} else {
+		resultList.SetBorderColor(tcell.ColorWhite)
+	}
+	a.resultList = resultList
+}
+
+// buildReview lists the open tasks of the days before today, keeping the
+// selected tasks.
+func (a *App) buildReview(fetchFromCache bool) {
+	if a.reviewList == nil {
+		a.reviewList = ui.NewReviewList(time.Now())
+		fetchFromCache = true
+	}
+	if fetchFromCache {
+		results, err := a.logService.OpenTasks(time.Now())
+		if err != nil {
+			zerolog.Print("Error reading open tasks ", err)
+		}
+		a.reviewList.SetTasks(results)
+	}
+	a.reviewList.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf("Review: %d open", len(a.reviewList.GetResults())))
+	if a.selectedView == Review {
+		a.reviewList.SetBorderColor(tcell.ColorBlue)
+	} else {
+		a.reviewList.SetBorderColor(tcell.ColorWhite)
+	}
+}
+
+// buildHistory lists the days the log was migrated through, highlighting the
+// title when it was migrated more times than the warning.
+func (a *App) buildHistory() {
+	history, err := a.logService.MigrationHistory(a.historyId)
+	if err != nil {
+		zerolog.Print("Error reading history ", err)
+	}
+	historyList := ui.NewResultList().SetResults(history)
+	if a.historyList != nil {
+		historyList.SetCurrentItem(a.historyList.GetCurrentItem())
+	}
+	historyList.SetSelectedFunc(func(result search.Result) {
+		a.showDate(result.Date)
+		a.dailyList.SelectLog(result.Log.Id)
+		a.selectedView = Today
+		a.rebuild(false)
+	})
+	migrations := 0
+	if len(history) > 0 {
+		migrations = history[len(history)-1].Log.Migrations
+	}
+	historyList.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf("History: migrated %d times", migrations))
+	if a.migrationWarning > 0 && migrations > a.migrationWarning {
+		historyList.SetTitleColor(tcell.ColorOrangeRed)
+	}
+	if a.selectedView == History {
+		historyList.SetBorderColor(tcell.ColorBlue)
+	} else {
+		historyList.SetBorderColor(tcell.ColorWhite)
+	}
+	a.historyList = historyList
+}
+
+// buildImportant lists the important tasks still open in the journal.
+func (a *App) buildImportant() {
+	results, err := a.logService.ImportantTasks()
+	if err != nil {
+		zerolog.Print("Error reading important tasks ", err)
+	}
+	importantList := ui.NewResultList().SetResults(results)
+	if a.importantList != nil {
+		importantList.SetCurrentItem(a.importantList.GetCurrentItem())
+	}
+	importantList.SetSelectedFunc(func(result search.Result) {
+		a.showDate(result.Date)
+		a.dailyList.SelectLog(result.Log.Id)
+		a.selectedView = Today
+		a.rebuild(false)
+	})
+	importantList.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf("Important: %d open", len(results)))
+	if a.selectedView == Important {
+		importantList.SetBorderColor(tcell.ColorBlue)
+	} else {
+		importantList.SetBorderColor(tcell.ColorWhite)
+	}
+	a.importantList = importantList
+}
+
+// tagListWidth is the width of the tags in the tag browser.
+const tagListWidth = 24
+
+// buildTags shows the tags of the journal next to the logs of the selected
+// one.
+func (a *App) buildTags(fetchFromCache bool) *tview.Flex {
+	if a.tagList == nil {
+		a.tagList = ui.NewTagList()
+		fetchFromCache = true
+	}
+	if fetchFromCache {
+		tags, err := a.logService.Tags()
+		if err != nil {
+			zerolog.Print("Error reading tags ", err)
+		}
+		a.tagList.SetTags(tags)
+	}
+	tag, _ := a.tagList.GetCurrentTag()
+	results, err := a.logService.TaggedLogs(tag.Tag)
+	if err != nil || tag.Tag == "" {
+		results = nil
+	}
+	taggedList := ui.NewResultList().SetResults(results)
+	if a.taggedList != nil {
+		taggedList.SetCurrentItem(a.taggedList.GetCurrentItem())
+	}
+	taggedList.SetSelectedFunc(func(result search.Result) {
+		a.showDate(result.Date)
+		a.dailyList.SelectLog(result.Log.Id)
+		a.selectedView = Today
+		a.rebuild(false)
+	})
+	a.tagList.
+		SetBorder(true).
+		SetTitle("Tags")
+	taggedList.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf("%v: %d entries", tag.Tag, len(results)))
+	a.tagList.SetBorderColor(tcell.ColorWhite)
+	taggedList.SetBorderColor(tcell.ColorWhite)
+	if a.selectedView == Tags && a.taggedFocus {
+		taggedList.SetBorderColor(tcell.ColorBlue)
+	} else if a.selectedView == Tags {
+		a.tagList.SetBorderColor(tcell.ColorBlue)
+	}
+	a.taggedList = taggedList
+	return tview.NewFlex().
+		AddItem(a.tagList, tagListWidth, 0, false).
+		AddItem(taggedList, 0, 1, false)
+}
+
+// tagsInput sends the key to the tags or to the logs of the tag, Right and
+// Left move between them.
+func (a *App) tagsInput(event *tcell.EventKey) {
+	switch {
+	case event.Key() == tcell.KeyRight && !a.taggedFocus:
+		a.taggedFocus = true
+	case event.Key() == tcell.KeyLeft && a.taggedFocus:
+		a.taggedFocus = false
+	case a.taggedFocus:
+		handler := a.taggedList.InputHandler()
+		handler(event, func(p tview.Primitive) {})
+		return
+	default:
+		previous, _ := a.tagList.GetCurrentTag()
+		handler := a.tagList.InputHandler()
+		handler(event, func(p tview.Primitive) {})
+		if tag, _ := a.tagList.GetCurrentTag(); tag.Tag != previous.Tag {
+			a.taggedList = nil
+		}
+	}
+	a.rebuild(false)
+}
+
+// buildHabits shows the habits of the journal with the days they were done up
+// to the displayed day.
+func (a *App) buildHabits(fetchFromCache bool) {
+	if a.habitGrid == nil {
+		a.habitGrid = ui.NewHabitGrid(a.date).SetToggledFunc(a.checkHabit)
+		fetchFromCache = true
+	}
+	if fetchFromCache {
+		habitLog, err := a.logService.ReadHabits()
+		if err != nil {
+			zerolog.Print("Error reading habits ", err)
+		}
+		checkIns, err := a.logService.HabitCheckIns()
+		if err != nil {
+			zerolog.Print("Error reading habit check-ins ", err)
+		}
+		a.habitGrid.SetHabits(habitLog.Entries, checkIns)
+	}
+	a.habitGrid.
+		SetLast(a.date).
+		SetBorder(true).
+		SetTitle("Habits")
+	if a.selectedView == Habits {
+		a.habitGrid.SetBorderColor(tcell.ColorBlue)
+	} else {
+		a.habitGrid.SetBorderColor(tcell.ColorWhite)
+	}
+}
+
+// checkHabit marks the habit as done on the day, or as not done.
+func (a *App) checkHabit(habit model.Habit, date time.Time, done bool) {
+	if _, err := a.logService.CheckHabit(date, habit.Name, done); err != nil {
+		a.showMessage(err.Error())
+		return
+	}
+	a.rebuild(true)
+}
+
+// suggestTags completes the #tag or @context at the end of the text with the
+// ones used in the journal.
+func (a *App) suggestTags(text string) []string {
+	if a.promptMode != promptLog && a.promptMode != promptSearch && a.promptMode != promptEdit {
+		return nil
+	}
+	word := []rune(text[strings.LastIndexFunc(text, unicode.IsSpace)+1:])
+	if len(word) == 0 || (word[0] != model.TagPrefix && word[0] != model.ContextPrefix) {
+		return nil
+	}
+	prefix := strings.ToLower(string(word))
+	var suggestions []string
+	for _, tag := range a.tags {
+		if len(tag.Tag) > len(prefix) && strings.HasPrefix(tag.Tag, prefix) {
+			suggestions = append(suggestions, tag.Tag[len(prefix):])
+		}
+	}
+	return suggestions
+}
+
+// loadTags reads the tags suggested by the prompt, so the journal is not read
+// again on every key.
+func (a *App) loadTags() {
+	tags, err := a.logService.Tags()
+	if err != nil {
+		zerolog.Print("Error reading tags ", err)
+	}
+	a.tags = tags
+}
+
+// showHistory shows the migrations of the selected log, or hides them.
+func (a *App) showHistory() {
+	var id string
+	switch a.selectedView {
+	case PreviousDate:
+		id = selectedLogId(a.previousDayList)
+	case Today:
+		id = selectedLogId(a.dailyList)
+	case Search:
+		if index := a.resultList.GetCurrentItem(); index >= 0 {
+			id = a.resultList.GetResults()[index].Log.Id
+		}
+	}
+	if id == "" && a.panel != History {
+		return
+	}
+	a.historyId = id
+	a.historyList = nil
+	a.togglePanel(History)
+}
+
 func (a *App) showPrompt() {
+	a.promptMode = promptLog
+	a.loadTags()
 	a.showingPrompt = true
 	a.rebuild(false)
 }
 
 func (a *App) rebuild(fetchFromCache bool) {
-	itemsFlex := a.makeDayFlex(fetchFromCache)
+	var itemsFlex tview.Primitive
+	if a.showWeek {
+		itemsFlex = a.makeWeekFlex()
+	} else {
+		itemsFlex = a.makeDayFlex(fetchFromCache)
+	}
 	a.mainFlex.Clear()
 	if a.showingPrompt {
 		a.prompt = ui.NewPrompt(false)
 		a.prompt.SetModel(a.buffer)
-		a.prompt.SetIcon(a.selectedCategory.Print())
+		switch a.promptMode {
+		case promptDate:
+			a.prompt.SetIcon('@')
+		case promptSearch:
+			a.prompt.SetIcon('/')
+		case promptSchedule:
+			a.prompt.SetIcon('<')
+		case promptEdit:
+			a.prompt.SetIcon('~')
+		default:
+			a.prompt.SetIcon(a.selectedCategory.Print())
+		}
 		a.mainFlex.
 			AddItemAtIndex(0, a.prompt, 3, 1, false)
 	}
 	a.mainFlex.AddItem(itemsFlex, 0, 1, false)
+	if a.statusMessage != "" {
+		a.status = tview.NewTextView().SetText(a.statusMessage)
+		a.status.SetTextColor(tcell.ColorOrangeRed)
+		a.mainFlex.AddItem(a.status, 1, 0, false)
+	}
+}
+
+// showMessage shows the message in the status line until the next key.
+func (a *App) showMessage(message string) {
+	a.statusMessage = message
+	a.rebuild(false)
+}
+
+// DayChanged refreshes the days after their files were changed by another
+// process. It can be called from any goroutine.
+func (a *App) DayChanged(event service.ChangeEvent) {
+	a.app.QueueUpdateDraw(func() {
+		if event.Conflict {
+			a.statusMessage = fmt.Sprintf("%v changed on disk while it had unsaved changes, their version was saved as %v.conflict.yaml",
+				timeconv.TimeToDayString(event.Date), timeconv.TimeToDayString(event.Date))
+		}
+		a.rebuild(true)
+	})
 }
 
 func (a *App) hidePrompt() {
@@ -136,6 +667,21 @@ func (a *App) Show() {
 
 	a.rebuild(true)
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if a.confirmed != nil {
+			confirmed := a.confirmed
+			a.confirmed = nil
+			a.statusMessage = ""
+			if event.Key() == tcell.KeyRune && event.Rune() == 'y' {
+				confirmed()
+			}
+			a.rebuild(true)
+			// The key answered the question, it is not an action.
+			return nil
+		}
+		if a.statusMessage != "" {
+			a.statusMessage = ""
+			a.rebuild(false)
+		}
 		if a.showingPrompt {
 			a.prompt.GetInputCapture()(event)
 		} else {
@@ -153,71 +699,77 @@ func (a *App) Show() {
 				a.selectedCategory = &category
 				a.showPrompt()
 			case event.Key() == tcell.KeyRune && event.Rune() == 'c': // Complete
-				var actualLog *model.Log
-				var dateTime time.Time
-				switch a.selectedView {
-				case PreviousDate:
-					actualLog = a.previousDayList.GetCurrentLog()
-					dateTime = a.previousDayList.GetDaily().Date
-				case Today:
-					actualLog = a.dailyList.GetCurrentLog()
-					dateTime = a.dailyList.GetDaily().Date
-				}
-				if actualLog != nil {
-					actualLog.MarkAsComplete()
-					_, err := a.logService.SaveLog(dateTime)
-					if err != nil {
-						zerolog.Print("Error saving log", err)
-					}
-				}
+				a.markCurrentLog(model.Complete)
 			case event.Key() == tcell.KeyRune && event.Rune() == 'i': // Irrelevant
-				var actualLog *model.Log
-				var dateTime time.Time
-				switch a.selectedView {
-				case PreviousDate:
-					actualLog = a.previousDayList.GetCurrentLog()
-					dateTime = a.previousDayList.GetDaily().Date
-				case Today:
-					actualLog = a.dailyList.GetCurrentLog()
-					dateTime = a.dailyList.GetDaily().Date
-				}
-				if actualLog != nil {
-					actualLog.MarkAsIrrelevant()
-					_, err := a.logService.SaveLog(dateTime)
-					if err != nil {
-						zerolog.Print("Error saving log", err)
-					}
-				}
+				a.markCurrentLog(model.Irrelevant)
 			case event.Key() == tcell.KeyRune && event.Rune() == 'm': // Migrate
-				if a.selectedView == PreviousDate {
-					previousLog := a.previousDayList.GetCurrentLog()
-					if previousLog != nil {
-						_, err := a.logService.MoveExistingLog(time.Now(), *previousLog)
-						if err != nil {
-							zerolog.Print("Error saving log", err)
-						}
-						previousLog.MarkAsMigrated()
-						_, err = a.logService.SaveLog(a.previousDayList.GetDaily().Date)
-						if err != nil {
-							zerolog.Print("Error saving log", err)
-						}
-					}
-				}
+				a.migrateCurrentLog()
+			case event.Key() == tcell.KeyRune && event.Rune() == '[': // Previous day
+				a.showDate(a.date.AddDate(0, 0, -1))
+			case event.Key() == tcell.KeyRune && event.Rune() == ']': // Next day
+				a.showDate(a.date.AddDate(0, 0, 1))
+			case event.Key() == tcell.KeyRune && event.Rune() == 'w': // Show Week
+				a.showWeek = !a.showWeek
+				a.panel = Today
+				a.selectedView = Today
 				a.rebuild(true)
+			case event.Key() == tcell.KeyRune && event.Rune() == '/': // Search
+				a.promptMode = promptSearch
+				a.loadTags()
+				a.showingPrompt = true
+				a.rebuild(false)
+			case event.Key() == tcell.KeyRune && event.Rune() == 'r': // Review open tasks
+				a.reviewList = nil
+				a.togglePanel(Review)
+			case event.Key() == tcell.KeyRune && event.Rune() == 'u': // Undo
+				a.undo("Undone", a.logService.Undo)
+			case event.Key() == tcell.KeyCtrlR: // Redo
+				a.undo("Redone", a.logService.Redo)
+			case event.Key() == tcell.KeyRune && event.Rune() == 'E': // Edit
+				a.editCurrentLog()
+			case event.Key() == tcell.KeyRune && event.Rune() == 'd': // Delete
+				a.deleteCurrentLog()
+			case event.Key() == tcell.KeyRune && event.Rune() == 'K': // Move up
+				a.rearrangeCurrentLog(func(id string) (model.DailyLog, error) { return a.logService.MoveLog(id, -1) })
+			case event.Key() == tcell.KeyRune && event.Rune() == 'J': // Move down
+				a.rearrangeCurrentLog(func(id string) (model.DailyLog, error) { return a.logService.MoveLog(id, 1) })
+			case event.Key() == tcell.KeyRune && event.Rune() == '>': // Indent
+				a.rearrangeCurrentLog(a.logService.IndentLog)
+			case event.Key() == tcell.KeyRune && event.Rune() == '<': // Outdent
+				a.rearrangeCurrentLog(a.logService.OutdentLog)
+			case event.Key() == tcell.KeyRune && event.Rune() == 'h': // Migration history
+				a.showHistory()
+			case event.Key() == tcell.KeyRune && event.Rune() == '*': // Important
+				a.toggleImportant()
+			case event.Key() == tcell.KeyRune && event.Rune() == 'p': // Important open tasks
+				a.togglePanel(Important)
+			case event.Key() == tcell.KeyRune && event.Rune() == '#': // Tag browser
+				a.tagList = nil
+				a.taggedList = nil
+				a.taggedFocus = false
+				a.togglePanel(Tags)
+			case event.Key() == tcell.KeyRune && event.Rune() == 'H': // Habit tracker
+				a.habitGrid = nil
+				a.togglePanel(Habits)
+			case event.Key() == tcell.KeyRune && event.Rune() == 'f': // Order by importance
+				a.importantMode = a.importantMode.Next()
+				a.statusMessage = fmt.Sprintf("Showing %v", a.importantMode)
+				a.rebuild(true)
+			case event.Key() == tcell.KeyRune && event.Rune() == 's': // Schedule
+				a.promptMode = promptSchedule
+				a.showingPrompt = true
+				a.rebuild(false)
+			case event.Key() == tcell.KeyRune && event.Rune() == 'g': // Go to date
+				a.promptMode = promptDate
+				a.showingPrompt = true
+				a.rebuild(false)
 			case event.Key() == tcell.KeyCtrlL:
-				a.buildPreviousDay(time.Now())
+				a.buildPreviousDay(a.date)
 				previousLog := a.previousDayList.GetDaily()
 				if previousLog != nil {
-					for i, _ := range previousLog.Logs {
-						_, err := a.logService.MoveExistingLog(time.Now(), previousLog.Logs[i])
-						if err != nil {
-							zerolog.Print("Error saving log", err)
-						}
-						previousLog.Logs[i].MarkAsMigrated()
-						_, err = a.logService.SaveLog(a.previousDayList.GetDaily().Date)
-						if err != nil {
-							zerolog.Print("Error saving log", err)
-						}
+					_, err := a.logService.MigrateDay(previousLog.Date, a.date)
+					if err != nil {
+						zerolog.Print("Error saving log", err)
 					}
 				}
 				a.rebuild(true)
@@ -229,33 +781,37 @@ func (a *App) Show() {
 						a.logService.OpenIndexItem(*indexItem)
 					}
 				}
-			case event.Key() == tcell.KeyCtrlP: // Show Previous Day
-				a.showPreviousDay = !a.showPreviousDay
-				a.selectedView = PreviousDate
-				if a.showIndex && a.showPreviousDay {
-					a.showIndex = false
+				if a.selectedView == Search {
+					handler := a.resultList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
 				}
-				a.rebuild(true)
-				if !a.showPreviousDay {
-					a.selectedView = Today
+				if a.selectedView == History {
+					handler := a.historyList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
 				}
-			case event.Key() == tcell.KeyCtrlI: // Show Index
-				a.showIndex = !a.showIndex
-				a.selectedView = Index
-				if a.showPreviousDay && a.showIndex {
-					a.showPreviousDay = false
+				if a.selectedView == Important {
+					handler := a.importantList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
 				}
-				a.rebuild(true)
+				if a.selectedView == Tags && a.taggedFocus {
+					handler := a.taggedList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
+				}
+				if a.selectedView == Habits {
+					handler := a.habitGrid.InputHandler()
+					handler(event, func(p tview.Primitive) {})
+				}
+			case event.Key() == tcell.KeyCtrlP: // Show Previous Day
+				a.togglePanel(PreviousDate)
+			case event.Key() == tcell.KeyCtrlI: // Show Index
+				a.togglePanel(Index)
+			case event.Key() == tcell.KeyCtrlO: // Show Monthly Log
+				a.togglePanel(Monthly)
 			case event.Key() == tcell.KeyCtrlJ: // Jump between views
 				if a.selectedView != Today {
 					a.selectedView = Today
 				} else {
-					switch {
-					case a.showPreviousDay:
-						a.selectedView = PreviousDate
-					case a.showIndex:
-						a.selectedView = Index
-					}
+					a.selectedView = a.panel
 				}
 				a.rebuild(true)
 			default:
@@ -269,6 +825,26 @@ func (a *App) Show() {
 				case Index:
 					handler := a.indexList.InputHandler()
 					handler(event, func(p tview.Primitive) {})
+				case Monthly:
+					handler := a.monthlyList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
+				case Search:
+					handler := a.resultList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
+				case Review:
+					handler := a.reviewList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
+				case History:
+					handler := a.historyList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
+				case Important:
+					handler := a.importantList.InputHandler()
+					handler(event, func(p tview.Primitive) {})
+				case Tags:
+					a.tagsInput(event)
+				case Habits:
+					handler := a.habitGrid.InputHandler()
+					handler(event, func(p tview.Primitive) {})
 				}
 			}
 		}
@@ -280,12 +856,232 @@ func (a *App) Show() {
 	}
 }
 
+// undo undoes or redoes the last command, showing which one.
+func (a *App) undo(done string, action func() (service.Command, error)) {
+	command, err := action()
+	if err != nil {
+		a.statusMessage = err.Error()
+	} else {
+		a.statusMessage = fmt.Sprintf("%v: %v", done, command.Name)
+	}
+	a.rebuild(true)
+}
+
+// currentLog returns the selected log of the focused day, nil if there is
+// none.
+func (a *App) currentLog() *model.Log {
+	switch a.selectedView {
+	case PreviousDate:
+		return a.previousDayList.GetCurrentLog()
+	case Today:
+		return a.dailyList.GetCurrentLog()
+	}
+	return nil
+}
+
+// editCurrentLog opens the prompt with the name of the selected log.
+func (a *App) editCurrentLog() {
+	log := a.currentLog()
+	if log == nil {
+		return
+	}
+	a.editId = log.Id
+	a.promptMode = promptEdit
+	a.loadTags()
+	a.showingPrompt = true
+	a.rebuild(false)
+	text := log.Name
+	if moment := log.Moment(); moment != nil {
+		text = fmt.Sprintf("%v %c%v", text, model.MomentPrefix, moment)
+	}
+	a.buffer.SetText(text)
+}
+
+// renameLog renames the edited log with the text of the prompt, an empty text
+// keeps the name.
+func (a *App) renameLog(text string) {
+	a.buffer.ClearText(true)
+	a.hidePrompt()
+	if len(text) == 0 {
+		return
+	}
+	name, moment, err := model.ParseMomentText(text, a.date)
+	if err == nil {
+		_, err = a.logService.RenameLog(a.editId, name, moment)
+	}
+	if err != nil {
+		a.showMessage(err.Error())
+		return
+	}
+	a.rebuild(true)
+}
+
+// deleteCurrentLog asks to confirm the deletion of the selected log and its
+// sub logs.
+func (a *App) deleteCurrentLog() {
+	log := a.currentLog()
+	if log == nil {
+		return
+	}
+	id := log.Id
+	question := fmt.Sprintf("Delete %q? (y/n)", log.Name)
+	if count := log.CountSubLogs(); count > 0 {
+		question = fmt.Sprintf("Delete %q and its %d sub entries? (y/n)", log.Name, count)
+	}
+	a.confirmed = func() {
+		if err := a.logService.DeleteLog(id); err != nil {
+			zerolog.Print("Error deleting log", err)
+		}
+	}
+	a.showMessage(question)
+}
+
+// rearrangeCurrentLog moves the selected log, keeping it selected.
+func (a *App) rearrangeCurrentLog(rearrange func(id string) (model.DailyLog, error)) {
+	log := a.currentLog()
+	if log == nil {
+		return
+	}
+	if _, err := rearrange(log.Id); err != nil && !errors.Is(err, service.ErrCantMove) {
+		zerolog.Print("Error saving log", err)
+	}
+	a.rebuild(true)
+}
+
+// toggleImportant marks the selected log as important, or removes the mark.
+func (a *App) toggleImportant() {
+	var id string
+	if log := a.currentLog(); log != nil {
+		id = log.Id
+	}
+	if a.selectedView == Important {
+		if index := a.importantList.GetCurrentItem(); index >= 0 {
+			id = a.importantList.GetResults()[index].Log.Id
+		}
+	}
+	if id == "" {
+		return
+	}
+	if _, _, err := a.logService.ToggleImportant(id); err != nil {
+		zerolog.Print("Error saving log", err)
+	}
+	a.rebuild(true)
+}
+
+// warnMigrations shows a message when the copy of the log migrated to the day
+// was migrated more times than the warning.
+func (a *App) warnMigrations(dailyLog model.DailyLog, id string) {
+	if a.migrationWarning == 0 {
+		return
+	}
+	for _, log := range dailyLog.Logs {
+		if log.MigratedFrom != nil && log.MigratedFrom.Id == id && log.Migrations > a.migrationWarning {
+			a.statusMessage = fmt.Sprintf("%q was migrated %d times, do it, schedule it or drop it", log.Name, log.Migrations)
+		}
+	}
+}
+
+// migrateCurrentLog migrates the selected logs of the focused panel: the ones
+// of the previous day, the review and the monthly log to the displayed day, and
+// the one of a column of the week to today, or to the next day when the column
+// is today or later.
+func (a *App) migrateCurrentLog() {
+	switch a.selectedView {
+	case PreviousDate:
+		previousLog := a.previousDayList.GetCurrentLog()
+		if previousLog != nil {
+			dailyLog, err := a.logService.MigrateLog(previousLog.Id, a.date)
+			if err != nil {
+				zerolog.Print("Error saving log", err)
+			}
+			a.warnMigrations(dailyLog, previousLog.Id)
+		}
+	case Review:
+		err := a.logService.MigrateLogs(a.reviewList.GetSelectedIds(), time.Now())
+		if err != nil {
+			zerolog.Print("Error saving log", err)
+		}
+	case Monthly:
+		monthlyLog := a.monthlyList.GetCurrentLog()
+		if monthlyLog != nil {
+			_, err := a.logService.MigrateMonthlyLog(a.monthlyList.GetMonthly().Date, monthlyLog.Id, a.date)
+			if err != nil {
+				zerolog.Print("Error saving log", err)
+			}
+		}
+	case Today:
+		currentLog := a.dailyList.GetCurrentLog()
+		if a.showWeek && currentLog != nil {
+			to := time.Now()
+			if !a.date.Before(time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location())) {
+				to = a.date.AddDate(0, 0, 1)
+			}
+			dailyLog, err := a.logService.MigrateLog(currentLog.Id, to)
+			if err != nil {
+				zerolog.Print("Error saving log", err)
+			}
+			a.warnMigrations(dailyLog, currentLog.Id)
+		}
+	}
+	a.rebuild(true)
+}
+
+// markCurrentLog applies the mark to the selected log of the focused day.
+func (a *App) markCurrentLog(mark model.Category) {
+	var actualLog *model.Log
+	switch a.selectedView {
+	case PreviousDate:
+		actualLog = a.previousDayList.GetCurrentLog()
+	case Today:
+		actualLog = a.dailyList.GetCurrentLog()
+	case Monthly:
+		monthlyLog := a.monthlyList.GetCurrentLog()
+		if monthlyLog != nil {
+			_, err := a.logService.MarkMonthlyLog(a.monthlyList.GetMonthly().Date, monthlyLog.Id, mark)
+			if err != nil {
+				zerolog.Print("Error saving log", err)
+			}
+		}
+		a.rebuild(true)
+		return
+	case Review:
+		err := a.logService.MarkLogs(a.reviewList.GetSelectedIds(), mark)
+		if err != nil {
+			zerolog.Print("Error saving log", err)
+		}
+		a.rebuild(true)
+		return
+	}
+	if actualLog == nil {
+		return
+	}
+	_, err := a.logService.MarkLog(actualLog.Id, mark)
+	if err != nil {
+		zerolog.Print("Error saving log", err)
+	}
+	a.rebuild(true)
+}
+
 func (a *App) BufferCompleted(text string) {}
 
 func (a *App) BufferChanged(text string) {}
 
 func (a *App) BufferActive(state bool) {
 	if state == false {
+		switch a.promptMode {
+		case promptDate:
+			a.goToDate(a.buffer.GetText())
+			return
+		case promptSearch:
+			a.search(a.buffer.GetText())
+			return
+		case promptSchedule:
+			a.schedule(a.buffer.GetText())
+			return
+		case promptEdit:
+			a.renameLog(a.buffer.GetText())
+			return
+		}
 		if a.selectedCategory == nil {
 			log.Print("Buffer complete without selected category")
 			os.Exit(101)
@@ -298,24 +1094,33 @@ func (a *App) BufferActive(state bool) {
 			return
 		}
 
-		var selectedLog *model.Log
-		index := a.dailyList.GetCurrentItem()
-		if index >= 0 {
-			selectedLog = a.dailyList.GetItem(index)
-			if selectedLog.Parent != nil {
-				selectedLog = selectedLog.Parent
-			}
+		if a.selectedView == Monthly {
+			a.addMonthlyLog(a.buffer.GetText())
+			return
 		}
+
+		// The new log is a sub log of the selected one, at any depth.
+		selectedLog := a.dailyList.GetCurrentLog()
 		text := a.buffer.GetText()
-		if len(text) != 0 {
+		// The moment written after ^ is when the task is due or the event
+		// happens.
+		name, moment, err := model.ParseMomentText(text, a.date)
+		if err != nil {
+			a.showingPrompt = false
+			a.buffer.ClearText(true)
+			a.showMessage(err.Error())
+			return
+		}
+		if len(name) != 0 {
+			newLog := model.NewLog(name, *a.selectedCategory)
+			newLog.SetMoment(moment)
 			if selectedLog != nil {
-				selectedLog.AppendNewSubLog(text, *a.selectedCategory)
-				_, err := a.logService.SaveLog(time.Now())
+				_, err := a.logService.AppendLog(selectedLog.Id, a.date, newLog)
 				if err != nil {
 					return
 				}
 			} else {
-				_, err := a.logService.AddNewLog(time.Now(), text, *a.selectedCategory)
+				_, err := a.logService.AddLog(a.date, newLog)
 				if err != nil {
 					return
 				}
@@ -327,3 +1132,97 @@ func (a *App) BufferActive(state bool) {
 		a.rebuild(true)
 	}
 }
+
+// addMonthlyLog adds the text to the monthly log, events with a day selected
+// go to the calendar and the rest to the task list of the month.
+func (a *App) addMonthlyLog(text string) {
+	if len(text) != 0 {
+		monthly := a.monthlyList.GetMonthly()
+		log := model.NewLog(text, *a.selectedCategory)
+		var err error
+		if day := a.monthlyList.GetCurrentDay(); day != 0 && *a.selectedCategory == model.Event {
+			_, err = a.logService.AddMonthlyEvent(monthly.DayDate(day), log)
+		} else {
+			_, err = a.logService.AddMonthlyLog(monthly.Date, log)
+		}
+		if err != nil {
+			zerolog.Print("Error saving log", err)
+		}
+	}
+	a.buffer.ClearText(true)
+	a.hidePrompt()
+	a.rebuild(true)
+}
+
+// showDate shows the day of the date.
+func (a *App) showDate(date time.Time) {
+	a.date = date
+	a.dailyList = nil
+	a.rebuild(true)
+}
+
+// goToDate shows the day written in the prompt, an empty text keeps the
+// current day.
+func (a *App) goToDate(text string) {
+	a.buffer.ClearText(true)
+	a.hidePrompt()
+	if len(text) == 0 {
+		return
+	}
+	date, err := timeconv.ParseDate(text, time.Now())
+	if err != nil {
+		a.showMessage(err.Error())
+		return
+	}
+	a.showDate(date)
+}
+
+// search shows the results of the query written in the prompt.
+func (a *App) search(text string) {
+	a.buffer.ClearText(true)
+	a.hidePrompt()
+	if len(text) == 0 {
+		return
+	}
+	if _, err := search.Parse(text, time.Now()); err != nil {
+		a.showMessage(err.Error())
+		return
+	}
+	a.searchQuery = text
+	a.resultList = nil
+	a.showWeek = false
+	a.panel = Search
+	a.selectedView = Search
+	a.rebuild(true)
+}
+
+// schedule moves the selected tasks to the future log, for the month or the
+// date written in the prompt.
+func (a *App) schedule(text string) {
+	a.buffer.ClearText(true)
+	a.hidePrompt()
+	if len(text) == 0 {
+		return
+	}
+	var ids []string
+	switch a.selectedView {
+	case Review:
+		ids = a.reviewList.GetSelectedIds()
+	case PreviousDate:
+		ids = []string{selectedLogId(a.previousDayList)}
+	case Today:
+		ids = []string{selectedLogId(a.dailyList)}
+	}
+	if len(ids) == 0 || ids[0] == "" {
+		return
+	}
+	date, wholeMonth, err := timeconv.ParseMonthOrDate(text, time.Now())
+	if err == nil {
+		err = a.logService.ScheduleLogs(ids, date, wholeMonth)
+	}
+	if err != nil {
+		a.showMessage(err.Error())
+		return
+	}
+	a.rebuild(true)
+}
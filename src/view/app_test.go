@@ -0,0 +1,48 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloa/bjournal/src/model"
+	"github.com/apoloa/bjournal/src/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateInWeekColumn(t *testing.T) {
+	logService := service.NewLogService(t.TempDir())
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	past := today.AddDate(0, 0, -3)
+	for _, date := range []time.Time{past, today} {
+		_, err := logService.AddNewLog(date, "Call the bank", model.Task)
+		assert.Nil(t, err)
+	}
+
+	app := NewApp(logService)
+	app.showWeek = true
+	// A task of a past column goes to today.
+	app.date = past
+	app.rebuild(true)
+	app.dailyList.SetCurrentItem(0)
+	app.migrateCurrentLog()
+	dailyLog, err := logService.ReadDay(past)
+	assert.Nil(t, err)
+	assert.True(t, dailyLog.Logs[0].IsMigrated())
+	dailyLog, err = logService.ReadDay(today)
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 2)
+
+	// A task of today goes to the next day.
+	app.date = today
+	app.rebuild(true)
+	app.dailyList.SetCurrentItem(0)
+	app.migrateCurrentLog()
+	dailyLog, err = logService.ReadDay(today)
+	assert.Nil(t, err)
+	assert.True(t, dailyLog.Logs[0].IsMigrated())
+	dailyLog, err = logService.ReadDay(today.AddDate(0, 0, 1))
+	assert.Nil(t, err)
+	assert.Len(t, dailyLog.Logs, 1)
+	assert.Equal(t, "Call the bank", dailyLog.Logs[0].Name)
+}